@@ -0,0 +1,149 @@
+// Package checklist provides methods for querying the relationship between SFO Museum collection objects and the exhibitions they
+// have been shown in.
+package checklist
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/collection"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+)
+
+// Returns all the collection.Object instances shown in the exhibition(s) matching 'code'.
+func ObjectsInExhibition(ctx context.Context, code string, states ...curatorial.ExistentialState) ([]*collection.Object, error) {
+
+	exhibitions_lookup, err := exhibitions.NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new exhibitions lookup, %w", err)
+	}
+
+	collection_lookup, err := collection.NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new collection lookup, %w", err)
+	}
+
+	return ObjectsInExhibitionWithLookups(ctx, exhibitions_lookup, collection_lookup, code, states...)
+}
+
+// Returns all the collection.Object instances shown in the exhibition(s) matching 'code' with custom curatorial.Lookup instances.
+// If 'states' is not empty only objects in those existential states are returned.
+func ObjectsInExhibitionWithLookups(ctx context.Context, exhibitions_lookup curatorial.Lookup, collection_lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) ([]*collection.Object, error) {
+
+	exhibitions_list, err := exhibitions.FindExhibitions(ctx, exhibitions_lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*collection.Object, 0)
+	seen_exhibitions := make(map[int64]bool)
+	seen_objects := make(map[int64]bool)
+
+	for _, e := range exhibitions_list {
+
+		if seen_exhibitions[e.SFOMuseumId] {
+			continue
+		}
+
+		seen_exhibitions[e.SFOMuseumId] = true
+
+		rsp, err := collection.FindObjectsInExhibition(ctx, collection_lookup, e.SFOMuseumId, states...)
+
+		if err != nil {
+
+			if collection.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, o := range rsp {
+
+			if seen_objects[o.WhosOnFirstId] {
+				continue
+			}
+
+			seen_objects[o.WhosOnFirstId] = true
+			objects = append(objects, o)
+		}
+	}
+
+	return objects, nil
+}
+
+// Returns all the exhibitions.Exhibition instances that the object(s) matching 'code' have been shown in.
+func ExhibitionsForObject(ctx context.Context, code string, states ...curatorial.ExistentialState) ([]*exhibitions.Exhibition, error) {
+
+	exhibitions_lookup, err := exhibitions.NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new exhibitions lookup, %w", err)
+	}
+
+	collection_lookup, err := collection.NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new collection lookup, %w", err)
+	}
+
+	return ExhibitionsForObjectWithLookups(ctx, exhibitions_lookup, collection_lookup, code, states...)
+}
+
+// Returns all the exhibitions.Exhibition instances that the object(s) matching 'code' (for example an accession number) have been
+// shown in with custom curatorial.Lookup instances. If 'states' is not empty only exhibitions in those existential states are returned.
+func ExhibitionsForObjectWithLookups(ctx context.Context, exhibitions_lookup curatorial.Lookup, collection_lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) ([]*exhibitions.Exhibition, error) {
+
+	objects, err := collection.FindObjects(ctx, collection_lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exhibition_ids := make([]int64, 0)
+
+	for _, o := range objects {
+
+		for _, id := range o.ExhibitionIds {
+
+			if !slices.Contains(exhibition_ids, id) {
+				exhibition_ids = append(exhibition_ids, id)
+			}
+		}
+	}
+
+	exhibitions_list := make([]*exhibitions.Exhibition, 0)
+	seen := make(map[int64]bool)
+
+	for _, id := range exhibition_ids {
+
+		exhibition_code := fmt.Sprintf("sfomuseum:exhibition_id=%d", id)
+		rsp, err := exhibitions.FindExhibitions(ctx, exhibitions_lookup, exhibition_code, states...)
+
+		if err != nil {
+
+			if exhibitions.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, e := range rsp {
+
+			if seen[e.WhosOnFirstId] {
+				continue
+			}
+
+			seen[e.WhosOnFirstId] = true
+			exhibitions_list = append(exhibitions_list, e)
+		}
+	}
+
+	return exhibitions_list, nil
+}
@@ -0,0 +1,124 @@
+package checklist
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/collection"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+)
+
+func newChecklistTestLookups(t *testing.T) (curatorial.Lookup, curatorial.Lookup) {
+
+	t.Helper()
+
+	ctx := context.Background()
+
+	exhibitions_list := []*exhibitions.Exhibition{
+		&exhibitions.Exhibition{WhosOnFirstId: 101, SFOMuseumId: 1845, Name: "Parasols", IsCurrent: 0},
+		&exhibitions.Exhibition{WhosOnFirstId: 102, SFOMuseumId: 1846, Name: "Shields", IsCurrent: 1},
+		&exhibitions.Exhibition{WhosOnFirstId: 103, SFOMuseumId: 1847, Name: "Empty", IsCurrent: 1},
+	}
+
+	objects_list := []*collection.Object{
+		&collection.Object{WhosOnFirstId: 201, SFOMuseumId: 1, AccessionNumber: "2005.132.040.008", IsCurrent: 1, ExhibitionIds: []int64{1845, 1846}},
+		&collection.Object{WhosOnFirstId: 202, SFOMuseumId: 2, AccessionNumber: "2005.132.040.009", IsCurrent: 1, ExhibitionIds: []int64{1845}},
+		&collection.Object{WhosOnFirstId: 203, SFOMuseumId: 3, AccessionNumber: "2005.132.040.010", IsCurrent: 1},
+	}
+
+	exhibitions_lookup, err := exhibitions.NewLookupWithLookupFunc(ctx, exhibitions.NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create exhibitions lookup, %v", err)
+	}
+
+	collection_lookup, err := collection.NewLookupWithLookupFunc(ctx, collection.NewLookupFuncWithCollection(ctx, objects_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create collection lookup, %v", err)
+	}
+
+	return exhibitions_lookup, collection_lookup
+}
+
+func TestObjectsInExhibition(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_lookup, collection_lookup := newChecklistTestLookups(t)
+
+	tests := map[string][]int64{
+		"1845": []int64{201, 202},
+		"102":  []int64{201},
+		"1847": []int64{},
+	}
+
+	for code, expected := range tests {
+
+		objects, err := ObjectsInExhibitionWithLookups(ctx, exhibitions_lookup, collection_lookup, code)
+
+		if err != nil {
+			t.Fatalf("Failed to find objects in exhibition %s, %v", code, err)
+		}
+
+		ids := make([]int64, len(objects))
+
+		for idx, o := range objects {
+			ids[idx] = o.WhosOnFirstId
+		}
+
+		if !slices.Equal(ids, expected) {
+			t.Fatalf("Unexpected objects in exhibition %s. Got %v but expected %v", code, ids, expected)
+		}
+	}
+}
+
+func TestExhibitionsForObject(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_lookup, collection_lookup := newChecklistTestLookups(t)
+
+	tests := map[string][]int64{
+		"2005.132.040.008": []int64{101, 102},
+		"2005.132.040.009": []int64{101},
+		"2005.132.040.010": []int64{},
+	}
+
+	for code, expected := range tests {
+
+		exhibitions_list, err := ExhibitionsForObjectWithLookups(ctx, exhibitions_lookup, collection_lookup, code)
+
+		if err != nil {
+			t.Fatalf("Failed to find exhibitions for object %s, %v", code, err)
+		}
+
+		ids := make([]int64, len(exhibitions_list))
+
+		for idx, e := range exhibitions_list {
+			ids[idx] = e.WhosOnFirstId
+		}
+
+		if !slices.Equal(ids, expected) {
+			t.Fatalf("Unexpected exhibitions for object %s. Got %v but expected %v", code, ids, expected)
+		}
+	}
+
+	current, err := ExhibitionsForObjectWithLookups(ctx, exhibitions_lookup, collection_lookup, "2005.132.040.008", curatorial.StateCurrent)
+
+	if err != nil {
+		t.Fatalf("Failed to find current exhibitions for object, %v", err)
+	}
+
+	if len(current) != 1 || current[0].WhosOnFirstId != 102 {
+		t.Fatalf("Unexpected current exhibitions for object")
+	}
+
+	_, err = ExhibitionsForObjectWithLookups(ctx, exhibitions_lookup, collection_lookup, "1999.1")
+
+	if !collection.IsNotFound(err) {
+		t.Fatalf("Expected unknown object to return not found error, %v", err)
+	}
+}
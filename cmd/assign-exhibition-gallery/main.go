@@ -1,19 +1,21 @@
 // assign-exhibition-gallery is a command line tool to update wof:parent_id and wof:hierarchy information
-// for a SFO Museum exhibition record derived from one or more SFO Museum gallery records.
+// for a SFO Museum exhibition record derived from one or more SFO Museum gallery records. Multiple exhibitions
+// can be updated in a single run by passing a CSV or JSON lines manifest using the -manifest flag.
 package main
 
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/sfomuseum/go-flags/multi"
-	wof_reader "github.com/whosonfirst/go-whosonfirst-reader/v2"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/diff"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions/edit"
 	sfom_writer "github.com/sfomuseum/go-sfomuseum-writer/v3"
-	"github.com/tidwall/gjson"
 	"github.com/whosonfirst/go-reader/v2"
-	"github.com/whosonfirst/go-whosonfirst-export/v3"
-	"github.com/whosonfirst/go-whosonfirst-feature/properties"
 	"github.com/whosonfirst/go-writer/v3"
 )
 
@@ -22,15 +24,37 @@ func main() {
 	architecture_reader_uri := flag.String("architecture-reader-uri", "repo:///usr/local/data/sfomuseum-data-architecture", "")
 	exhibitions_reader_uri := flag.String("exhibitions-reader-uri", "repo:///usr/local/data/sfomuseum-data-exhibition", "")
 	exhibitions_writer_uri := flag.String("exhibitions-writer-uri", "", "If empty, the value of the -exhibitions-reader-uri flag will be used.")
-	exhibition_id := flag.Int64("exhibition-id", 0, "The SFO Museum exhibition ID to update.")
+	exhibitions_lookup_uri := flag.String("exhibitions-lookup-uri", "exhibitions://", "A valid sfomuseum/go-sfomuseum-curatorial/exhibitions lookup URI used to resolve exhibition identifiers.")
+	exhibition_id := flag.String("exhibition-id", "", "The identifier of the exhibition to update. This may be any code understood by the exhibitions lookup, for example a Who's On First ID, \"sfomuseum:exhibition_id={ID}\" or \"sfomuseum_www:exhibition_id={ID}\". If the identifier matches more than one exhibition the current exhibition is used.")
 
 	var gallery_ids multi.MultiInt64
 	flag.Var(&gallery_ids, "gallery-id", "One or more SFO Museum gallery IDs.")
 
+	follow_superseded := flag.Bool("follow-superseded", false, "If true and a gallery record has been superseded then follow its wof:superseded_by chain and assign the current gallery instead.")
+
+	multi_gallery_geometry := flag.String("multi-gallery-geometry", edit.GEOMETRY_MULTIPOINT, "The type of geometry to assign to exhibitions spanning multiple galleries. Valid options are: multipoint (the centroids of each gallery), multipolygon (the union of the footprints of each gallery, with the walls shared by adjacent galleries dissolved, as a MultiPolygon along with recomputed label centroid and bounding box properties).")
+
+	manifest := flag.String("manifest", "", "The path to a manifest of exhibition IDs and gallery IDs to process. If present the -exhibition-id and -gallery-id flags are ignored. Rows without gallery IDs are rejected unless they explicitly unassign all galleries (a \"none\" gallery column in CSV manifests or \"unassign\": true in JSON lines manifests). Use \"-\" to read from STDIN.")
+	manifest_format := flag.String("manifest-format", "", "The format of the manifest. Valid options are: csv, jsonl. If empty the format is derived from the manifest's file extension.")
+
+	dry_run := flag.Bool("dry-run", false, "If true, print a property-level diff of the changes that would be made without writing anything.")
+	dry_run_format := flag.String("dry-run-format", diff.TEXT, "The format for -dry-run output. Valid options are: text, json.")
+
 	flag.Parse()
 
 	ctx := context.Background()
 
+	switch *multi_gallery_geometry {
+	case edit.GEOMETRY_MULTIPOINT, edit.GEOMETRY_MULTIPOLYGON:
+		// pass
+	default:
+		log.Fatalf("Invalid -multi-gallery-geometry flag '%s'", *multi_gallery_geometry)
+	}
+
+	assign_opts := &edit.AssignGalleriesOptions{
+		MultiGalleryGeometry: *multi_gallery_geometry,
+	}
+
 	if *exhibitions_writer_uri == "" {
 		*exhibitions_writer_uri = *exhibitions_reader_uri
 	}
@@ -53,75 +77,118 @@ func main() {
 		log.Fatalf("Failed to create exhibitions writer, %v", err)
 	}
 
-	exh_f, err := wof_reader.LoadBytes(ctx, exh_r, *exhibition_id)
+	exh_lookup, err := exhibitions.NewLookup(ctx, *exhibitions_lookup_uri)
 
 	if err != nil {
-		log.Fatalf("Failed to load exhibition record, %v", err)
+		log.Fatalf("Failed to create exhibitions lookup, %v", err)
 	}
 
-	galleries := make([][]byte, len(gallery_ids))
+	assign := func(ctx context.Context, code string, gallery_ids []int64) error {
 
-	for idx, gal_id := range gallery_ids {
-
-		gal_f, err := wof_reader.LoadBytes(ctx, arch_r, gal_id)
+		exh_f, err := edit.LoadExhibition(ctx, exh_lookup, exh_r, code)
 
 		if err != nil {
-			log.Fatalf("Failed to load gallery record %d, %v", gal_id, err)
+			return err
 		}
 
-		galleries[idx] = gal_f
-	}
-
-	updates := make(map[string]interface{})
-
-	switch len(galleries) {
-	case 0:
-		updates["properties.wof:parent_id"] = -1
-		updates["properties.wof:hierarchy"] = make([]map[string]int64, 0)
-	case 1:
-		updates["properties.wof:parent_id"] = gjson.GetBytes(galleries[0], "properties.wof:id").Int()
-		updates["properties.wof:hierarchy"] = gjson.GetBytes(galleries[0], "properties.wof:hierarchy").Value()
-		updates["properties.sfomuseum:post_security"] = gjson.GetBytes(galleries[0], "properties.sfomuseum:post_security").Value()
-		updates["geometry"] = gjson.GetBytes(galleries[0], "geometry").Value()
-	default:
+		galleries := make([][]byte, len(gallery_ids))
 
-		hiers := make([]map[string]interface{}, 0)
-		coords := make([][]float64, 0)
+		for idx, gal_id := range gallery_ids {
 
-		for _, body := range galleries {
+			gal_f, err := edit.LoadGallery(ctx, arch_r, gal_id, *follow_superseded)
 
-			for _, r := range gjson.GetBytes(body, "properties.wof:hierarchy").Array() {
-				hiers = append(hiers, r.Value().(map[string]interface{}))
+			if err != nil {
+				return err
 			}
 
-			pt, _, err := properties.Centroid(body)
+			galleries[idx] = gal_f
+		}
+
+		has_updates, new_exh_f, err := edit.AssignGalleries(ctx, exh_f, galleries, assign_opts)
+
+		if err != nil {
+			return fmt.Errorf("Failed to assign galleries, %w", err)
+		}
+
+		if *dry_run {
+
+			d, err := diff.Compare(exh_f, new_exh_f)
 
 			if err != nil {
-				log.Fatalf("Failed to derive centroid for gallery, %v", err)
+				return fmt.Errorf("Failed to derive diff, %w", err)
 			}
 
-			coords = append(coords, []float64{pt.X(), pt.Y()})
+			return d.Write(os.Stdout, *dry_run_format)
 		}
 
-		updates["properties.wof:parent_id"] = -4
-		updates["properties.wof:hierarchy"] = hiers
+		exh_f = new_exh_f
+
+		if has_updates {
 
-		updates["geometry.type"] = "MultiPoint"
-		updates["geometry.coordinates"] = coords
+			_, err := sfom_writer.WriteBytes(ctx, exh_wr, exh_f)
+
+			if err != nil {
+				return fmt.Errorf("Failed to write updates, %w", err)
+			}
+		}
+
+		return nil
 	}
 
-	has_updates, exh_f, err := export.AssignPropertiesIfChanged(ctx, exh_f, updates)
+	if *manifest == "" {
+
+		err = assign(ctx, *exhibition_id, gallery_ids)
+
+		if err != nil {
+			log.Fatalf("Failed to assign galleries to exhibition %s, %v", *exhibition_id, err)
+		}
+
+		return
+	}
+
+	entries, err := readManifest(*manifest, *manifest_format)
 
 	if err != nil {
-		log.Fatalf("Failed to assign properties, %v", err)
+		log.Fatalf("Failed to read manifest, %v", err)
 	}
 
-	if has_updates {
+	failed := 0
+
+	for _, e := range entries {
 
-		_, err := sfom_writer.WriteBytes(ctx, exh_wr, exh_f)
+		if e.Error != nil {
+			log.Printf("[%d] Failed to parse manifest entry, %v", e.Row, e.Error)
+			failed += 1
+			continue
+		}
+
+		err := assign(ctx, e.ExhibitionId, e.GalleryIds)
 
 		if err != nil {
-			log.Fatalf("Failed to write updates, %v", err)
+			log.Printf("[%d] Failed to assign galleries %v to exhibition %s, %v", e.Row, e.GalleryIds, e.ExhibitionId, err)
+			failed += 1
+			continue
+		}
+
+		switch {
+		case e.Unassign && *dry_run:
+			log.Printf("[%d] Would unassign all galleries from exhibition %s", e.Row, e.ExhibitionId)
+		case e.Unassign:
+			log.Printf("[%d] Unassigned all galleries from exhibition %s", e.Row, e.ExhibitionId)
+		case *dry_run:
+			log.Printf("[%d] Would assign galleries %v to exhibition %s", e.Row, e.GalleryIds, e.ExhibitionId)
+		default:
+			log.Printf("[%d] Assigned galleries %v to exhibition %s", e.Row, e.GalleryIds, e.ExhibitionId)
 		}
 	}
+
+	if *dry_run {
+		log.Printf("Processed %d manifest entries (dry run, nothing was written): %d would succeed, %d failed", len(entries), len(entries)-failed, failed)
+	} else {
+		log.Printf("Processed %d manifest entries: %d succeeded, %d failed", len(entries), len(entries)-failed, failed)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// MANIFEST_UNASSIGN is the value of a CSV manifest's gallery column used to explicitly unassign all the galleries from an exhibition.
+const MANIFEST_UNASSIGN string = "none"
+
+// manifestEntry is a single row in a manifest of exhibition IDs and the gallery IDs to assign to them.
+type manifestEntry struct {
+	// The (1-based) line number in the manifest that the entry starts on.
+	Row int
+	// The identifier of the exhibition to update. This may be any code understood by the exhibitions lookup.
+	ExhibitionId string
+	// The Who's On First IDs of the galleries to assign to the exhibition.
+	GalleryIds []int64
+	// A boolean flag indicating that the manifest explicitly asked for all the galleries to be unassigned from the exhibition.
+	Unassign bool
+	// Any error encountered parsing the entry.
+	Error error
+}
+
+// readManifest reads the manifest at 'path' (or STDIN if 'path' is "-") in 'format' which is expected to be one of "csv" or
+// "jsonl". If 'format' is empty it is derived from the extension of 'path'. Errors parsing individual rows are recorded in the
+// corresponding `manifestEntry` rather than causing the entire manifest to fail.
+func readManifest(path string, format string) ([]*manifestEntry, error) {
+
+	if format == "" {
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".jsonl", ".json", ".ndjson":
+			format = "jsonl"
+		default:
+			return nil, fmt.Errorf("Unable to derive manifest format for '%s'", path)
+		}
+	}
+
+	var r io.Reader
+
+	if path == "-" {
+		r = os.Stdin
+	} else {
+
+		fh, err := os.Open(path)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to open %s, %w", path, err)
+		}
+
+		defer fh.Close()
+		r = fh
+	}
+
+	switch format {
+	case "csv":
+		return readCSVManifest(r)
+	case "jsonl":
+		return readJSONLManifest(r)
+	default:
+		return nil, fmt.Errorf("Invalid manifest format '%s'", format)
+	}
+}
+
+// readCSVManifest reads a CSV manifest where the first column is an exhibition identifier and the remaining columns are gallery IDs.
+// Gallery ID columns may also contain multiple IDs separated by spaces, line breaks or semi-colons. A header row is skipped if the first
+// column of the first record is "exhibition_id" or "exhibition". Rows without any gallery IDs are treated as errors unless their only gallery
+// column is "none" in which case all the galleries are unassigned from the exhibition.
+func readCSVManifest(r io.Reader) ([]*manifestEntry, error) {
+
+	csv_r := csv.NewReader(r)
+	csv_r.FieldsPerRecord = -1
+	csv_r.TrimLeadingSpace = true
+
+	entries := make([]*manifestEntry, 0)
+	seen_first := false
+
+	for {
+
+		record, err := csv_r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+
+			// Reminder: csv.ParseError.StartLine is the line that the record (which may span multiple lines) starts on
+
+			var parse_err *csv.ParseError
+
+			if !errors.As(err, &parse_err) {
+				return nil, fmt.Errorf("Failed to read CSV manifest, %w", err)
+			}
+
+			seen_first = true
+			entries = append(entries, &manifestEntry{Row: parse_err.StartLine, Error: err})
+			continue
+		}
+
+		// Row numbers are the line numbers that records start on rather than the number of records read, since
+		// blank lines are skipped and quoted fields may span multiple lines.
+
+		row, _ := csv_r.FieldPos(0)
+
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+
+		exhibition_id := strings.TrimSpace(record[0])
+
+		is_first := !seen_first
+		seen_first = true
+
+		if is_first && isManifestHeader(exhibition_id) {
+			continue
+		}
+
+		if exhibition_id == "" {
+			entries = append(entries, &manifestEntry{Row: row, Error: fmt.Errorf("Missing exhibition ID")})
+			continue
+		}
+
+		e := &manifestEntry{
+			Row:          row,
+			ExhibitionId: exhibition_id,
+			GalleryIds:   make([]int64, 0),
+		}
+
+		entries = append(entries, e)
+
+		for _, col := range record[1:] {
+
+			for _, str_id := range strings.FieldsFunc(col, isManifestSeparator) {
+
+				if strings.ToLower(str_id) == MANIFEST_UNASSIGN {
+					e.Unassign = true
+					continue
+				}
+
+				gallery_id, err := strconv.ParseInt(str_id, 10, 64)
+
+				if err != nil {
+					e.Error = fmt.Errorf("Invalid gallery ID '%s', %w", str_id, err)
+					break
+				}
+
+				e.GalleryIds = append(e.GalleryIds, gallery_id)
+			}
+		}
+
+		if e.Error == nil {
+			e.Error = validateManifestEntry(e)
+		}
+	}
+
+	return entries, nil
+}
+
+// readJSONLManifest reads a manifest where each line is a JSON object with "exhibition_id" (a string or a number) and "gallery_ids" properties.
+// Lines without any gallery IDs are treated as errors unless they contain an "unassign" property set to true in which case all the
+// galleries are unassigned from the exhibition.
+func readJSONLManifest(r io.Reader) ([]*manifestEntry, error) {
+
+	scanner := bufio.NewScanner(r)
+
+	entries := make([]*manifestEntry, 0)
+	row := 0
+
+	for scanner.Scan() {
+
+		row += 1
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		e := &manifestEntry{
+			Row:        row,
+			GalleryIds: make([]int64, 0),
+		}
+
+		entries = append(entries, e)
+
+		if !gjson.Valid(line) {
+			e.Error = fmt.Errorf("Invalid JSON")
+			continue
+		}
+
+		// Exhibition identifiers may be encoded as either strings or numbers
+		exhibition_rsp := gjson.Get(line, "exhibition_id")
+
+		if !exhibition_rsp.Exists() || exhibition_rsp.String() == "" {
+			e.Error = fmt.Errorf("Missing exhibition_id property")
+			continue
+		}
+
+		e.ExhibitionId = exhibition_rsp.String()
+
+		for _, r := range gjson.Get(line, "gallery_ids").Array() {
+
+			if r.Type != gjson.Number {
+				e.Error = fmt.Errorf("Invalid gallery ID '%s'", r.Raw)
+				break
+			}
+
+			e.GalleryIds = append(e.GalleryIds, r.Int())
+		}
+
+		if e.Error != nil {
+			continue
+		}
+
+		e.Unassign = gjson.Get(line, "unassign").Bool()
+		e.Error = validateManifestEntry(e)
+	}
+
+	err := scanner.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read manifest, %w", err)
+	}
+
+	return entries, nil
+}
+
+// validateManifestEntry ensures that 'e' either lists one or more gallery IDs or explicitly asks for all the galleries to be unassigned
+// from the exhibition, but not both. This prevents empty cells from silently detaching exhibitions from their galleries.
+func validateManifestEntry(e *manifestEntry) error {
+
+	switch {
+	case e.Unassign && len(e.GalleryIds) > 0:
+		return fmt.Errorf("Entry lists gallery IDs and asks for galleries to be unassigned")
+	case !e.Unassign && len(e.GalleryIds) == 0:
+		return fmt.Errorf("Missing gallery IDs, use \"%s\" (CSV) or \"unassign\": true (JSON lines) to unassign all the galleries from an exhibition", MANIFEST_UNASSIGN)
+	default:
+		return nil
+	}
+}
+
+func isManifestHeader(col string) bool {
+
+	switch strings.ToLower(col) {
+	case "exhibition_id", "exhibition":
+		return true
+	default:
+		return false
+	}
+}
+
+func isManifestSeparator(r rune) bool {
+	return r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
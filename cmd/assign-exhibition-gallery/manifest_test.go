@@ -0,0 +1,145 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVManifest(t *testing.T) {
+
+	manifest := `exhibition_id,gallery_id
+1159159407,1745882083
+1159159408,1745882083;1745882085
+1159159409
+1159159410,
+1159159411,none
+1159159412,none,1745882083
+1159159413,abc
+`
+
+	entries, err := readCSVManifest(strings.NewReader(manifest))
+
+	if err != nil {
+		t.Fatalf("Failed to read manifest, %v", err)
+	}
+
+	checkManifestEntries(t, entries)
+}
+
+func TestReadCSVManifestRows(t *testing.T) {
+
+	// Blank lines are skipped and quoted fields may span multiple lines so row numbers are line numbers rather than record counts
+
+	manifest := `
+exhibition_id,gallery_id
+
+1159159407,1745882083
+1159159408,"1745882083
+1745882085"
+
+1159159409,1745882083
+`
+
+	entries, err := readCSVManifest(strings.NewReader(manifest))
+
+	if err != nil {
+		t.Fatalf("Failed to read manifest, %v", err)
+	}
+
+	expected := []int{4, 5, 8}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+
+	for idx, row := range expected {
+
+		e := entries[idx]
+
+		if e.Error != nil {
+			t.Fatalf("Unexpected error for entry %d, %v", idx, e.Error)
+		}
+
+		if e.Row != row {
+			t.Fatalf("Expected entry %d (%s) to be on row %d, got %d", idx, e.ExhibitionId, row, e.Row)
+		}
+	}
+
+	if !slices.Equal(entries[1].GalleryIds, []int64{1745882083, 1745882085}) {
+		t.Fatalf("Unexpected gallery IDs for multi-line entry, %v", entries[1].GalleryIds)
+	}
+}
+
+func TestReadJSONLManifest(t *testing.T) {
+
+	manifest := `{"exhibition_id": 1159159407, "gallery_ids": [1745882083]}
+{"exhibition_id": "1159159408", "gallery_ids": [1745882083, 1745882085]}
+{"exhibition_id": 1159159409}
+{"exhibition_id": 1159159410, "gallery_ids": []}
+{"exhibition_id": 1159159411, "unassign": true}
+{"exhibition_id": 1159159412, "gallery_ids": [1745882083], "unassign": true}
+{"exhibition_id": 1159159413, "gallery_ids": ["abc"]}
+`
+
+	entries, err := readJSONLManifest(strings.NewReader(manifest))
+
+	if err != nil {
+		t.Fatalf("Failed to read manifest, %v", err)
+	}
+
+	checkManifestEntries(t, entries)
+}
+
+// checkManifestEntries checks the entries derived from the equivalent CSV and JSON lines manifests in the tests above.
+func checkManifestEntries(t *testing.T, entries []*manifestEntry) {
+
+	tests := []struct {
+		ExhibitionId string
+		GalleryIds   []int64
+		Unassign     bool
+		Error        bool
+	}{
+		{"1159159407", []int64{1745882083}, false, false},
+		{"1159159408", []int64{1745882083, 1745882085}, false, false},
+		// No gallery IDs
+		{"1159159409", []int64{}, false, true},
+		// Empty gallery IDs
+		{"1159159410", []int64{}, false, true},
+		// Explicitly unassign all galleries
+		{"1159159411", []int64{}, true, false},
+		// Unassign and gallery IDs
+		{"1159159412", []int64{1745882083}, true, true},
+		// Invalid gallery ID
+		{"1159159413", []int64{}, false, true},
+	}
+
+	if len(entries) != len(tests) {
+		t.Fatalf("Expected %d entries, got %d", len(tests), len(entries))
+	}
+
+	for idx, expected := range tests {
+
+		e := entries[idx]
+
+		if e.ExhibitionId != expected.ExhibitionId {
+			t.Fatalf("Unexpected exhibition ID for entry %d: %s", idx, e.ExhibitionId)
+		}
+
+		if (e.Error != nil) != expected.Error {
+			t.Fatalf("Unexpected error for entry %d (%s): %v", idx, e.ExhibitionId, e.Error)
+		}
+
+		if expected.Error {
+			continue
+		}
+
+		if !slices.Equal(e.GalleryIds, expected.GalleryIds) {
+			t.Fatalf("Unexpected gallery IDs for entry %d (%s): %v", idx, e.ExhibitionId, e.GalleryIds)
+		}
+
+		if e.Unassign != expected.Unassign {
+			t.Fatalf("Unexpected unassign flag for entry %d (%s)", idx, e.ExhibitionId)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"flag"
 	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/sfomuseum/go-sfomuseum-curatorial/collection"
 )
@@ -16,7 +18,7 @@ func main() {
 	iterator_uri := flag.String("iterator-uri", "repo://?exclude=properties.edtf:deprecated=.*", "A valid whosonfirst/go-whosonfirst-iterate/v2 URI")
 	iterator_source := flag.String("iterator-source", "/usr/local/data/sfomuseum-data-collection", "The URI containing documents to iterate.")
 
-	target := flag.String("target", "data/collection.json", "The path to write SFO Museum collection data.")
+	target := flag.String("target", "data/collection.json.gz", "The path to write SFO Museum collection data. If the path ends in \".gz\" the data will be gzip-compressed.")
 	stdout := flag.Bool("stdout", false, "Emit SFO Museum collection data to SDOUT.")
 
 	flag.Parse()
@@ -25,13 +27,29 @@ func main() {
 
 	writers := make([]io.Writer, 0)
 
-	fh, err := os.OpenFile(*target, os.O_RDWR|os.O_CREATE, 0644)
+	fh, err := os.OpenFile(*target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
 
 	if err != nil {
 		log.Fatalf("Failed to open '%s', %v", *target, err)
 	}
 
-	writers = append(writers, fh)
+	defer fh.Close()
+
+	var gz *gzip.Writer
+
+	if strings.HasSuffix(*target, ".gz") {
+
+		gz, err = gzip.NewWriterLevel(fh, gzip.BestCompression)
+
+		if err != nil {
+			log.Fatalf("Failed to create gzip writer, %v", err)
+		}
+
+		writers = append(writers, gz)
+
+	} else {
+		writers = append(writers, fh)
+	}
 
 	if *stdout {
 		writers = append(writers, os.Stdout)
@@ -51,4 +69,13 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to marshal results, %v", err)
 	}
+
+	if gz != nil {
+
+		err = gz.Close()
+
+		if err != nil {
+			log.Fatalf("Failed to close gzip writer, %v", err)
+		}
+	}
 }
@@ -11,6 +11,7 @@ import (
 	_ "github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
 	_ "github.com/sfomuseum/go-sfomuseum-curatorial/publicart"
 	
+	"github.com/sfomuseum/go-flags/multi"
 	"github.com/sfomuseum/go-sfomuseum-curatorial"
 )
 
@@ -20,11 +21,29 @@ func main() {
 
 	lookup_uri_desc := fmt.Sprintf("Valid options are: %s", strings.Join(schemes, ", "))
 	lookup_uri := flag.String("lookup-uri", "", lookup_uri_desc)
+	search := flag.Bool("search", false, "Treat each argument as a (partial) name to search for rather than an identifier to look up. Results are ranked by relevance.")
+	limit := flag.Int("limit", 0, "The maximum number of search results to display for each query. If 0 all results are displayed.")
+
+	var state_labels multi.MultiString
+	flag.Var(&state_labels, "state", "Zero or more existential states used to filter results. Valid options are: current, not-current, unknown, deprecated, ceased, superseded. If empty all results are displayed.")
 
 	flag.Parse()
 
 	ctx := context.Background()
 
+	states := make([]curatorial.ExistentialState, len(state_labels))
+
+	for idx, label := range state_labels {
+
+		s, err := curatorial.ParseExistentialState(label)
+
+		if err != nil {
+			log.Fatalf("Invalid -state flag, %v", err)
+		}
+
+		states[idx] = s
+	}
+
 	lookup, err := curatorial.NewLookup(ctx, *lookup_uri)
 
 	if err != nil {
@@ -33,14 +52,58 @@ func main() {
 
 	for _, code := range flag.Args() {
 
+		if *search {
+
+			results, err := curatorial.SearchLookup(ctx, lookup, code)
+
+			if err != nil {
+				log.Fatalf("Failed to search for '%s', %v", code, err)
+			}
+
+			results = filterResults(results, states)
+
+			if *limit > 0 && len(results) > *limit {
+				results = results[0:*limit]
+			}
+
+			for _, a := range results {
+				fmt.Println(a)
+			}
+
+			continue
+		}
+
 		results, err := lookup.Find(ctx, code)
 
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		for _, a := range results {
+		for _, a := range filterResults(results, states) {
 			fmt.Println(a)
 		}
 	}
 }
+
+// filterResults returns the subset of 'results' that are in any of the existential states defined by 'states'.
+func filterResults(results []interface{}, states []curatorial.ExistentialState) []interface{} {
+
+	if len(states) == 0 {
+		return results
+	}
+
+	filtered := make([]interface{}, 0)
+
+	for _, a := range results {
+
+		r, ok := a.(curatorial.ExistentialRecord)
+
+		if !ok || !curatorial.MatchesExistentialStates(r, states...) {
+			continue
+		}
+
+		filtered = append(filtered, a)
+	}
+
+	return filtered
+}
@@ -5,12 +5,16 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
-	wof_reader "github.com/whosonfirst/go-whosonfirst-reader/v2"
-	sfom_writer "github.com/sfomuseum/go-sfomuseum-writer/v3"
+	"github.com/sfomuseum/go-flags/multi"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/diff"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions/edit"
 	"github.com/tidwall/gjson"
 	"github.com/whosonfirst/go-reader/v2"
 	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
 	"github.com/whosonfirst/go-whosonfirst-id"
 	"github.com/whosonfirst/go-writer/v3"
 )
@@ -22,8 +26,17 @@ func main() {
 	exhibition_reader_uri := flag.String("exhibitions-reader-uri", "repo:///usr/local/data/sfomuseum-data-exhibition", "")
 	exhibition_writer_uri := flag.String("exhibitions-writer-uri", "", "If empty, the value of the -exhibition-reader-uri flag will be used.")
 
-	exhibition_id := flag.Int64("exhibition-id", 0, "The SFO Museum exhibition ID to supersede")
-	parent_id := flag.Int64("parent-id", 0, "The SFO Museum parent ID of the new exhibition")
+	exhibitions_lookup_uri := flag.String("exhibitions-lookup-uri", "exhibitions://", "A valid sfomuseum/go-sfomuseum-curatorial/exhibitions lookup URI used to resolve exhibition identifiers.")
+	exhibition_id := flag.String("exhibition-id", "", "The identifier of the exhibition to supersede. This may be any code understood by the exhibitions lookup, for example a Who's On First ID, \"sfomuseum:exhibition_id={ID}\" or \"sfomuseum_www:exhibition_id={ID}\". If the identifier matches more than one exhibition the current exhibition is used.")
+	parent_id := flag.Int64("parent-id", 0, "The SFO Museum parent ID of the new exhibition. If the parent has itself been superseded then a new exhibition record will be created for each parent record in its supersedes chain whose dates overlap the exhibition's dates.")
+
+	id_provider_uri := flag.String("id-provider-uri", "proxy://?provider=whosonfirst://", "A valid aaronland/go-uid provider URI used to create the IDs of new exhibition records. For example \"sequence://?start={ID}\" will assign sequential IDs without talking to any remote services. This flag is ignored if -dry-run is true.")
+
+	var new_ids multi.MultiInt64
+	flag.Var(&new_ids, "new-id", "One or more explicit IDs to assign to new exhibition records, in order. If present the -id-provider-uri flag is ignored and there must be exactly one ID for each new record.")
+
+	dry_run := flag.Bool("dry-run", false, "If true, print a property-level diff of every record that would be created or modified without writing anything. Unless the -new-id flag is present new records are assigned placeholder (sequential) IDs starting at 1.")
+	dry_run_format := flag.String("dry-run-format", diff.TEXT, "The format for -dry-run output. Valid options are: text, json.")
 
 	flag.Parse()
 
@@ -51,91 +64,132 @@ func main() {
 		log.Fatalf("Failed to create exhibition writer, %v", err)
 	}
 
-	supersede := func(ctx context.Context, exhibition_id int64, parent_id int64) error {
+	supersede := func(ctx context.Context, exh_f []byte, parent_id int64) error {
 
-		id_provider, err := id.NewProvider(ctx)
+		parents, err := edit.Epochs(ctx, arch_r, parent_id, properties.Cessation(exh_f))
 
 		if err != nil {
-			return fmt.Errorf("Failed to create ID provider, %w", err)
+			return fmt.Errorf("Failed to derive parent records, %w", err)
 		}
 
-		exh_f, err := wof_reader.LoadBytes(ctx, exh_r, exhibition_id)
+		var id_provider id.Provider
 
-		if err != nil {
-			return fmt.Errorf("Failed to load exhibition record, %w", err)
+		if len(new_ids) > 0 {
+
+			if len(new_ids) != len(parents) {
+				return fmt.Errorf("Superseding exhibition creates %d new records but %d IDs were specified", len(parents), len(new_ids))
+			}
+
+			id_provider = edit.NewListProvider(new_ids...)
+
+		} else {
+
+			provider_uri := *id_provider_uri
+
+			// Nothing is written during a dry run so placeholder IDs are used rather than minting real ones
+
+			if *dry_run {
+				provider_uri = fmt.Sprintf("%s://", edit.SEQUENCE_SCHEME)
+			}
+
+			pr, err := id.NewProviderWithURI(ctx, provider_uri)
+
+			if err != nil {
+				return fmt.Errorf("Failed to create ID provider, %w", err)
+			}
+
+			id_provider = pr
 		}
 
-		new_parent_f, err := wof_reader.LoadBytes(ctx, arch_r, parent_id)
+		records, err := edit.SupersedeEpochs(ctx, exh_f, parents, id_provider)
 
 		if err != nil {
-			return fmt.Errorf("Failed to load parent record, %w", err)
+			return fmt.Errorf("Failed to supersede exhibition, %w", err)
 		}
 
-		new_id, err := id_provider.NewID(ctx)
+		// All the records are staged, and validated, before any of them are written
+
+		ex, err := export.NewExporter(ctx, "sfomuseum://")
 
 		if err != nil {
-			return fmt.Errorf("Failed to create new ID, %w", err)
+			return fmt.Errorf("Failed to create exporter, %w", err)
 		}
 
-		new_updates := map[string]interface{}{
-			"properties.id":             new_id,
-			"properties.wof:id":         new_id,
-			"properties.wof:parent_id":  parent_id,
-			"properties.wof:hierarchy":  gjson.GetBytes(new_parent_f, "properties.wof:hierarchy").Value(),
-			"properties.mz:is_current":  gjson.GetBytes(new_parent_f, "properties.mz:is_current").Value(),
-			"properties.edtf:inception": gjson.GetBytes(new_parent_f, "properties.edtf:inception").Value(),
-			// "properties.edtf:cessation": gjson.GetBytes(new_parent_f, "properties.edtf:cessation").Value(),
-			"properties.wof:supersedes": []int64{exhibition_id},
-		}
+		tx := edit.NewTransaction(exh_wr, ex)
 
-		// Create and record the new exh
+		created := make([]string, 0)
 
-		_, new_exh, err := export.AssignPropertiesIfChanged(ctx, exh_f, new_updates)
+		for _, r := range records {
 
-		if err != nil {
-			return fmt.Errorf("Failed to export new exh, %w", err)
+			err := tx.Stage(ctx, r.Original, r.Body)
+
+			if err != nil {
+				return fmt.Errorf("Failed to stage exhibition %d, %w", r.Id, err)
+			}
+
+			if r.Original == nil {
+				created = append(created, fmt.Sprintf("%d (parent %d) superseding %v", r.Id, gjson.GetBytes(r.Body, "properties.wof:parent_id").Int(), properties.Supersedes(r.Body)))
+			}
 		}
 
-		_, err = sfom_writer.WriteBytes(ctx, exh_wr, new_exh)
+		err = tx.Validate()
 
 		if err != nil {
-			return fmt.Errorf("Failed to write new exhibition, %w", err)
+			return fmt.Errorf("Failed to validate staged records, nothing has been written, %w", err)
 		}
 
-		//
+		if *dry_run {
 
-		old_updates := map[string]interface{}{
-			"properties.wof:superseded_by": []int64{new_id},
-			"properties.edtf:cessation":    gjson.GetBytes(new_parent_f, "properties.edtf:inception").Value(),
-		}
+			for _, r := range tx.Records() {
 
-		// Now update the previous exh
+				d, err := diff.Compare(r.Original, r.Body)
 
-		_, exh_f, err = export.AssignPropertiesIfChanged(ctx, exh_f, old_updates)
+				if err != nil {
+					return fmt.Errorf("Failed to derive diff for %d, %w", r.Id, err)
+				}
 
-		if err != nil {
-			return fmt.Errorf("Failed to export new exhibition, %w", err)
+				err = d.Write(os.Stdout, *dry_run_format)
+
+				if err != nil {
+					return fmt.Errorf("Failed to write diff for %d, %w", r.Id, err)
+				}
+			}
+
+			for _, label := range created {
+				log.Printf("Would create new exhibition with ID %s\n", label)
+			}
+
+			return nil
 		}
 
-		_, err = sfom_writer.WriteBytes(ctx, exh_wr, exh_f)
+		err = tx.Commit(ctx)
 
 		if err != nil {
-			return fmt.Errorf("Failed to write previous exhibition, %w", err)
+			return err
 		}
 
-		log.Printf("Created new exhibition with ID %d\n", new_id)
-
-		// Something something something.
-		// Do this recursively based on the end date of the exhibition
-		// relative to the end date of the parent (architecture).
-		// Something something something
+		for _, label := range created {
+			log.Printf("Created new exhibition with ID %s\n", label)
+		}
 
 		return nil
 	}
 
-	err = supersede(ctx, *exhibition_id, *parent_id)
+	exh_lookup, err := exhibitions.NewLookup(ctx, *exhibitions_lookup_uri)
+
+	if err != nil {
+		log.Fatalf("Failed to create exhibitions lookup, %v", err)
+	}
+
+	exh_f, err := edit.LoadExhibition(ctx, exh_lookup, exh_r, *exhibition_id)
+
+	if err != nil {
+		log.Fatalf("Failed to load exhibition, %v", err)
+	}
+
+	err = supersede(ctx, exh_f, *parent_id)
 
 	if err != nil {
-		log.Fatalf("Failed to supersede exhibition (%d), %v", *exhibition_id, err)
+		log.Fatalf("Failed to supersede exhibition (%s), %v", *exhibition_id, err)
 	}
 }
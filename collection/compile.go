@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	_ "log"
+	"log/slog"
 
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
 	"github.com/tidwall/gjson"
 	"github.com/whosonfirst/go-whosonfirst-feature/properties"
 	"github.com/whosonfirst/go-whosonfirst-iterate/v3"
@@ -23,13 +25,13 @@ func CompileCollectionData(ctx context.Context, iterator_uri string, iterator_so
 	}
 
 	for rec, err := range iter.Iterate(ctx, iterator_sources...) {
-		
+
 		if err != nil {
 			return nil, fmt.Errorf("Failed to iterate sources, %w", err)
 		}
 
 		defer rec.Body.Close()
-		
+
 		select {
 		case <-ctx.Done():
 			break
@@ -65,10 +67,10 @@ func CompileCollectionData(ctx context.Context, iterator_uri string, iterator_so
 			return nil, fmt.Errorf("Failed to derive wof:name for %s, %w", rec.Path, err)
 		}
 
-		is_current, err := properties.IsCurrent(body)
+		existential_flags, err := curatorial.DeriveExistentialFlags(body)
 
 		if err != nil {
-			return nil, fmt.Errorf("Failed to derive is current for %s, %w", rec.Path, err)
+			return nil, fmt.Errorf("Failed to derive existential flags for %s, %w", rec.Path, err)
 		}
 
 		sfomid_rsp := gjson.GetBytes(body, "properties.sfomuseum:object_id")
@@ -77,10 +79,13 @@ func CompileCollectionData(ctx context.Context, iterator_uri string, iterator_so
 			return nil, fmt.Errorf("'%s' is missing sfomuseum:object_id property", rec.Path)
 		}
 
+		// Not every object has been assigned an accession number. These objects are still indexed but
+		// can only be looked up by their other identifiers.
+
 		accno_rsp := gjson.GetBytes(body, "properties.sfomuseum:accession_number")
 
-		if !sfomid_rsp.Exists() {
-			return nil, fmt.Errorf("'%s' is missing sfomuseum:accession_number property", rec.Path)
+		if !accno_rsp.Exists() || accno_rsp.String() == "" {
+			slog.Warn("Object is missing sfomuseum:accession_number property", "path", rec.Path, "id", wof_id)
 		}
 
 		w := &Object{
@@ -88,7 +93,10 @@ func CompileCollectionData(ctx context.Context, iterator_uri string, iterator_so
 			SFOMuseumId:     sfomid_rsp.Int(),
 			AccessionNumber: accno_rsp.String(),
 			Name:            wof_name,
-			IsCurrent:       is_current.Flag(),
+			IsCurrent:       existential_flags.IsCurrent,
+			IsDeprecated:    existential_flags.IsDeprecated,
+			IsCeased:        existential_flags.IsCeased,
+			IsSuperseded:    existential_flags.IsSuperseded,
 		}
 
 		callno_rsp := gjson.GetBytes(body, "properties.sfomuseum:callnumber")
@@ -97,8 +105,44 @@ func CompileCollectionData(ctx context.Context, iterator_uri string, iterator_so
 			w.CallNumber = callno_rsp.String()
 		}
 
+		w.ExhibitionIds = deriveExhibitionIds(body)
+
 		lookup = append(lookup, w)
 	}
 
 	return lookup, nil
 }
+
+// deriveExhibitionIds returns the unique list of SFO Museum exhibition IDs in the `sfomuseum:exhibition_id` property of 'body',
+// which may be either a single value or a list of values.
+func deriveExhibitionIds(body []byte) []int64 {
+
+	exhibition_ids := make([]int64, 0)
+	seen := make(map[int64]bool)
+
+	rsp := gjson.GetBytes(body, "properties.sfomuseum:exhibition_id")
+
+	if !rsp.Exists() {
+		return exhibition_ids
+	}
+
+	values := []gjson.Result{rsp}
+
+	if rsp.IsArray() {
+		values = rsp.Array()
+	}
+
+	for _, v := range values {
+
+		id := v.Int()
+
+		if id <= 0 || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		exhibition_ids = append(exhibition_ids, id)
+	}
+
+	return exhibition_ids
+}
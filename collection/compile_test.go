@@ -0,0 +1,77 @@
+package collection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDeriveExhibitionIds(t *testing.T) {
+
+	tests := map[string][]int64{
+		`{"properties":{}}`: []int64{},
+		`{"properties":{"sfomuseum:exhibition_id":1845}}`:          []int64{1845},
+		`{"properties":{"sfomuseum:exhibition_id":[1845,0,1846]}}`: []int64{1845, 1846},
+		`{"properties":{"sfomuseum:exhibition_id":[1845,1845]}}`:   []int64{1845},
+	}
+
+	for body, expected := range tests {
+
+		ids := deriveExhibitionIds([]byte(body))
+
+		if !slices.Equal(ids, expected) {
+			t.Fatalf("Unexpected exhibition IDs for %s. Got %v but expected %v", body, ids, expected)
+		}
+	}
+}
+
+func TestCompileCollectionDataMissingAccessionNumber(t *testing.T) {
+
+	ctx := context.Background()
+
+	records := map[string]string{
+		"1511936845.geojson": `{"type":"Feature","properties":{"wof:id":1511936845,"wof:name":"Object","mz:is_current":1,"sfomuseum:object_id":1001,"sfomuseum:accession_number":"2005.132.040.008"},"geometry":{"type":"Point","coordinates":[0,0]}}`,
+		"1511936846.geojson": `{"type":"Feature","properties":{"wof:id":1511936846,"wof:name":"Object","mz:is_current":1,"sfomuseum:object_id":1002},"geometry":{"type":"Point","coordinates":[0,0]}}`,
+	}
+
+	root := t.TempDir()
+
+	for fname, body := range records {
+
+		err := os.WriteFile(filepath.Join(root, fname), []byte(body), 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to write %s, %v", fname, err)
+		}
+	}
+
+	objects, err := CompileCollectionData(ctx, "directory://", root)
+
+	if err != nil {
+		t.Fatalf("Failed to compile collection data, %v", err)
+	}
+
+	if len(objects) != len(records) {
+		t.Fatalf("Expected %d objects, got %d", len(records), len(objects))
+	}
+
+	for _, o := range objects {
+
+		if o.WhosOnFirstId != 1511936846 {
+			continue
+		}
+
+		if o.AccessionNumber != "" {
+			t.Fatalf("Expected empty accession number for %d, got '%s'", o.WhosOnFirstId, o.AccessionNumber)
+		}
+
+		for _, code := range lookupKeys(o) {
+
+			if code == "" || code == "sfomuseum:accession_number=" {
+				t.Fatalf("Unexpected code '%s' for object without an accession number", code)
+			}
+		}
+	}
+}
@@ -2,210 +2,143 @@ package collection
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
 	"sync"
-	"sync/atomic"
 
 	"github.com/sfomuseum/go-sfomuseum-curatorial"
-	"github.com/sfomuseum/go-sfomuseum-curatorial/data"
 )
 
-var lookup_table *sync.Map
-var lookup_idx int64
-
-var lookup_init sync.Once
-var lookup_init_err error
-
-type CollectionLookupFunc func(context.Context)
-
-type CollectionLookup struct {
-	curatorial.Lookup
+// CollectionLookupFunc is a function that populates a `CollectionLookup` instance.
+type CollectionLookupFunc = curatorial.LookupTableFunc[*Object]
+
+// CollectionLookup implements the `curatorial.TypedLookup` interface for `*Object` records.
+type CollectionLookup = curatorial.LookupTable[*Object]
+
+var lookup_options = &curatorial.LookupTableOptions[*Object]{
+	Filename: "collection.json",
+	Keys:     lookupKeys,
+	Compile:  CompileCollectionData,
+	NotFound: func(code string) error {
+		return NotFound{code}
+	},
+	Name: func(data *Object) string {
+		return data.Name
+	},
+	Normalize: normalizeCode,
 }
 
+// default_lookup is the lookup table derived from the precompiled (embedded) data that is shared by all the callers of `NewTypedLookup`
+// (and `NewLookup`) with an empty URI. It is created the first time it is needed.
+var default_lookup = sync.OnceValues(func() (curatorial.TypedLookup[*Object], error) {
+	return curatorial.NewLookupTable(context.Background(), lookup_options, "")
+})
+
 func init() {
 	ctx := context.Background()
 	curatorial.RegisterLookup(ctx, "collection", NewLookup)
-	lookup_idx = int64(0)
 }
 
-// NewLookup will return an `curatorial.Lookup` instance. By default the lookup table is derived from precompiled (embedded) data in `data/collection.json`
-// by passing in `collection://` as the URI. It is also possible to create a new lookup table with the following URI options:
+// NewLookup will return an `curatorial.Lookup` instance. By default the lookup table is derived from precompiled (embedded) data in `data/collection.json.gz` (or, if absent,
+// `data/collection.json`) by passing in `collection://` as the URI. Collection data is not bundled with this package by default (it needs to be produced using the `compile-collection-data` tool)
+// so if it is absent a `curatorial.NoPrecompiledData` error is returned. It is also possible to create a new lookup table with the following URI options:
 //
 //	`collection://github`
 //
 // This will cause the lookup table to be derived from the data stored at https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/collection.json. This might be desirable if there have been updates to the underlying data that are not reflected in the locally installed package's pre-compiled data.
+// If the remote data can not be retrieved the precompiled (embedded) data is used instead. Downloaded data can be cached, and revalidated, locally
+// using the `cache={DIRECTORY}` parameter and an alternate location (for example a mirror) can be specified using the `base-url={URL}` parameter.
+// See `curatorial.NewGitHubOptionsFromQuery` for details.
 //
 //	`collection://iterator?uri={URI}&source={SOURCE}`
 //
 // This will cause the lookup table to be derived, at runtime, from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance. `{URI}` should be a valid `whosonfirst/go-whosonfirst-iterate/iterator` URI and `{SOURCE}` is one or more URIs for the iterator to process.
+//
+//	`collection://file?path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file (for example one produced by the `compile-collection-data` tool) stored at `{PATH}` on the local filesystem. If `{PATH}` ends in ".gz" the file is assumed to be gzip-compressed.
+//
+//	`collection://reader?uri={URI}&path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file read from a `whosonfirst/go-reader` instance. `{URI}` should be a valid `whosonfirst/go-reader` URI and `{PATH}` is the path of the file to read, relative to that reader. If `{PATH}` is empty then "collection.json" is used.
+//
+// Any of these URIs may also include a `?refresh={DURATION}` parameter (for example `collection://iterator?uri={URI}&source={SOURCE}&refresh=1h`)
+// which will cause the lookup table to be reloaded from its source periodically. Lookups can also be reloaded on demand using `curatorial.RefreshLookup`.
+//
+// If 'uri' is empty a lookup table derived from the precompiled (embedded) data is created once and shared by all subsequent callers (including convenience
+// methods like `FindCurrentObject`) rather than creating a new lookup table each time. All other URIs, including `collection://`, create a new, independent lookup table.
+//
+// Accession numbers and call numbers that can not be found as-is are matched using their normalized forms. See `NormalizeAccessionNumber` and `NormalizeCallNumber` for details.
 func NewLookup(ctx context.Context, uri string) (curatorial.Lookup, error) {
 
-	u, err := url.Parse(uri)
+	l, err := NewTypedLookup(ctx, uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, err
 	}
 
-	// Reminder: u.Scheme is used by the curatorial.Lookup constructor
-
-	switch u.Host {
-	case "iterator":
-
-		q := u.Query()
-
-		iterator_uri := q.Get("uri")
-		iterator_sources := q["source"]
-
-		return NewLookupFromIterator(ctx, iterator_uri, iterator_sources...)
-
-	case "github":
-
-		data_url := "https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/collection.json"
-		rsp, err := http.Get(data_url)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load remote data from Github, %w", err)
-		}
-
-		lookup_func := NewLookupFuncWithReader(ctx, rsp.Body)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
-
-	default:
-
-		fs := data.FS
-		fh, err := fs.Open("collection.json")
+	return curatorial.NewUntypedLookup(l), nil
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load local precompiled data, %w", err)
-		}
+// NewTypedLookup will return an `curatorial.TypedLookup` instance for `*Object` records. See `NewLookup` for details on the URIs supported.
+func NewTypedLookup(ctx context.Context, uri string) (curatorial.TypedLookup[*Object], error) {
 
-		lookup_func := NewLookupFuncWithReader(ctx, fh)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
+	if uri == "" {
+		return default_lookup()
 	}
+
+	return curatorial.NewLookupTable(ctx, lookup_options, uri)
 }
 
-// NewLookup will return an `CollectionLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
+// NewLookupFuncWithReader will return an `CollectionLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
 // `r` will be closed when the `CollectionLookupFunc` function instance is invoked.
-// It is assumed that the data in `r` will be formatted in the same way as the procompiled (embedded) data stored in `data/sfomuseum.json`.
+// It is assumed that the data in `r` will be formatted in the same way as the (uncompressed) precompiled (embedded) data stored in `data/collection.json.gz` or `data/collection.json`.
 func NewLookupFuncWithReader(ctx context.Context, r io.ReadCloser) CollectionLookupFunc {
-
-	defer r.Close()
-
-	var collection_list []*Object
-
-	dec := json.NewDecoder(r)
-	err := dec.Decode(&collection_list)
-
-	if err != nil {
-
-		lookup_func := func(ctx context.Context) {
-			lookup_init_err = err
-		}
-
-		return lookup_func
-	}
-
-	return NewLookupFuncWithCollection(ctx, collection_list)
+	return curatorial.NewLookupTableFuncWithReader[*Object](ctx, r)
 }
 
-// NewLookup will return an `CollectionLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `collection_list`.
+// NewLookupFuncWithCollection will return an `CollectionLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `collection_list`.
 func NewLookupFuncWithCollection(ctx context.Context, collection_list []*Object) CollectionLookupFunc {
-
-	lookup_func := func(ctx context.Context) {
-
-		table := new(sync.Map)
-
-		for _, data := range collection_list {
-
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				// pass
-			}
-
-			appendData(ctx, table, data)
-		}
-
-		lookup_table = table
-	}
-
-	return lookup_func
+	return curatorial.NewLookupTableFuncWithRecords(ctx, collection_list)
 }
 
 // NewLookupWithLookupFunc will return an `curatorial.Lookup` instance derived by data compiled using `lookup_func`.
 func NewLookupWithLookupFunc(ctx context.Context, lookup_func CollectionLookupFunc) (curatorial.Lookup, error) {
 
-	fn := func() {
-		lookup_func(ctx)
-	}
-
-	lookup_init.Do(fn)
-
-	if lookup_init_err != nil {
-		return nil, lookup_init_err
-	}
-
-	l := CollectionLookup{}
-	return &l, nil
-}
-
-func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
-
-	collection_list, err := CompileCollectionData(ctx, iterator_uri, iterator_sources...)
+	l, err := NewTypedLookupWithLookupFunc(ctx, lookup_func)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to compile collection data, %w", err)
+		return nil, err
 	}
 
-	lookup_func := NewLookupFuncWithCollection(ctx, collection_list)
-	return NewLookupWithLookupFunc(ctx, lookup_func)
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *CollectionLookup) Find(ctx context.Context, code string) ([]interface{}, error) {
-
-	pointers, ok := lookup_table.Load(code)
-
-	if !ok {
-		return nil, NotFound{code}
-	}
-
-	candidates := make([]interface{}, 0)
-
-	for _, p := range pointers.([]string) {
-
-		if !strings.HasPrefix(p, "pointer:") {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
+// NewTypedLookupWithLookupFunc will return an `curatorial.TypedLookup` instance derived by data compiled using `lookup_func`.
+func NewTypedLookupWithLookupFunc(ctx context.Context, lookup_func CollectionLookupFunc) (curatorial.TypedLookup[*Object], error) {
+	return curatorial.NewLookupTableWithLookupFunc(ctx, lookup_options, lookup_func)
+}
 
-		row, ok := lookup_table.Load(p)
+// NewLookupFromIterator will return an `curatorial.Lookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
 
-		if !ok {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
+	l, err := NewTypedLookupFromIterator(ctx, iterator_uri, iterator_sources...)
 
-		candidates = append(candidates, row.(*Object))
+	if err != nil {
+		return nil, err
 	}
 
-	return candidates, nil
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *CollectionLookup) Append(ctx context.Context, data interface{}) error {
-	return appendData(ctx, lookup_table, data.(*Object))
+// NewTypedLookupFromIterator will return an `curatorial.TypedLookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewTypedLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.TypedLookup[*Object], error) {
+	return curatorial.NewLookupTableFromIterator(ctx, lookup_options, iterator_uri, iterator_sources...)
 }
 
-func appendData(ctx context.Context, table *sync.Map, data *Object) error {
-
-	idx := atomic.AddInt64(&lookup_idx, 1)
-
-	pointer := fmt.Sprintf("pointer:%d", idx)
-	table.Store(pointer, data)
+// lookupKeys returns the list of codes that 'data' should be indexed by.
+func lookupKeys(data *Object) []string {
 
 	str_wofid := strconv.FormatInt(data.WhosOnFirstId, 10)
 	str_sfomid := strconv.FormatInt(data.SFOMuseumId, 10)
@@ -214,48 +147,30 @@ func appendData(ctx context.Context, table *sync.Map, data *Object) error {
 	possible_codes := []string{
 		str_wofid,
 		str_sfomid,
-		accno,
 		fmt.Sprintf("wof:id=%s", str_wofid),
 		fmt.Sprintf("sfomuseum:object_id=%s", str_sfomid),
-		fmt.Sprintf("sfomuseum:accession_number=%s", accno),
+	}
+
+	if accno != "" {
+		possible_codes = append(possible_codes, accno)
+		possible_codes = append(possible_codes, fmt.Sprintf("sfomuseum:accession_number=%s", accno))
+		possible_codes = append(possible_codes, accessionNumberCode(accno))
 	}
 
 	if data.CallNumber != "" {
 		possible_codes = append(possible_codes, data.CallNumber)
 		possible_codes = append(possible_codes, fmt.Sprintf("sfomuseum:callnumber=%s", data.CallNumber))
+		possible_codes = append(possible_codes, callNumberCode(data.CallNumber))
 	}
 
-	for _, code := range possible_codes {
-
-		if code == "" {
-			continue
-		}
-
-		pointers := make([]string, 0)
-		has_pointer := false
-
-		others, ok := table.Load(code)
-
-		if ok {
-
-			pointers = others.([]string)
-		}
-
-		for _, dupe := range pointers {
-
-			if dupe == pointer {
-				has_pointer = true
-				break
-			}
-		}
-
-		if has_pointer {
-			continue
-		}
-
-		pointers = append(pointers, pointer)
-		table.Store(code, pointers)
+	for _, exhibition_id := range data.ExhibitionIds {
+		possible_codes = append(possible_codes, exhibitionCode(exhibition_id))
 	}
 
-	return nil
+	return possible_codes
+}
+
+// exhibitionCode returns the code used to index objects by the SFO Museum ID of an exhibition they were shown in.
+func exhibitionCode(exhibition_id int64) string {
+	return fmt.Sprintf("sfomuseum:exhibition_id=%d", exhibition_id)
 }
@@ -17,37 +17,96 @@ func TestCollectionLookup(t *testing.T) {
 
 	schemes := []string{
 		"collection://",
-		// "collection://github",
+		"collection://github",
 	}
 
 	ctx := context.Background()
 
 	for _, s := range schemes {
 
-		lu, err := curatorial.NewLookup(ctx, s)
+		t.Run(s, func(t *testing.T) {
 
-		if err != nil {
-			t.Fatalf("Failed to create lookup for '%s', %v", s, err)
-		}
+			lu, err := curatorial.NewLookup(ctx, s)
 
-		for code, wofid := range wofid_tests {
-
-			results, err := lu.Find(ctx, code)
+			if curatorial.IsNoPrecompiledData(err) {
+				t.Skipf("No precompiled collection data available for '%s', %v", s, err)
+			}
 
 			if err != nil {
-				t.Fatalf("Unable to find '%s' using scheme '%s', %v", code, s, err)
+				t.Fatalf("Failed to create lookup for '%s', %v", s, err)
 			}
 
-			if len(results) != 1 {
-				t.Fatalf("Invalid results for '%s' using scheme '%s'", code, s)
-			}
+			for code, wofid := range wofid_tests {
+
+				results, err := lu.Find(ctx, code)
+
+				if err != nil {
+					t.Fatalf("Unable to find '%s' using scheme '%s', %v", code, s, err)
+				}
+
+				if len(results) != 1 {
+					t.Fatalf("Invalid results for '%s' using scheme '%s'", code, s)
+				}
 
-			a := results[0].(*Object)
+				a := results[0].(*Object)
 
-			if a.WhosOnFirstId != wofid {
-				t.Fatalf("Invalid match for '%s', expected %d but got %d using scheme '%s'", code, wofid, a.WhosOnFirstId, s)
+				if a.WhosOnFirstId != wofid {
+					t.Fatalf("Invalid match for '%s', expected %d but got %d using scheme '%s'", code, wofid, a.WhosOnFirstId, s)
+				}
 			}
+		})
+	}
+
+}
+
+func TestNormalizedCollectionLookup(t *testing.T) {
+
+	ctx := context.Background()
+
+	collection_list := []*Object{
+		&Object{WhosOnFirstId: 1511936845, SFOMuseumId: 1, AccessionNumber: "2005.132.040.008", Name: "Postcard", IsCurrent: 1},
+		&Object{WhosOnFirstId: 1511908275, SFOMuseumId: 2, AccessionNumber: "2010.0201.001", CallNumber: "HE9797.5.C23 S3 1931 c.1 SC ENV", Name: "Timetable", IsCurrent: 1},
+	}
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithCollection(ctx, collection_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	tests := map[string]int64{
+		"2005.132.040.008": 1511936845,
+		"2005.132.40.8":    1511936845,
+		" 2005.132.40.8":   1511936845,
+		"sfomuseum:accession_number=2005.132.40.8": 1511936845,
+		"2010.201.1":                                           1511908275,
+		"HE9797.5.C23 S3 1931 c.1 SC ENV":                      1511908275,
+		"he9797.5.c23 s3 1931 C.1 SC ENV":                      1511908275,
+		"HE9797.5 .C23  S3 1931 c. 1 SC ENV":                   1511908275,
+		"sfomuseum:callnumber=he9797.5.c23 s3 1931 c.1 sc env": 1511908275,
+	}
+
+	for code, wofid := range tests {
+
+		o, err := FindCurrentObjectWithLookup(ctx, lookup, code)
+
+		if err != nil {
+			t.Fatalf("Failed to find object for '%s', %v", code, err)
+		}
+
+		if o.WhosOnFirstId != wofid {
+			t.Fatalf("Invalid match for '%s', expected %d but got %d", code, wofid, o.WhosOnFirstId)
 		}
 	}
 
+	// Prefixed codes are only normalized as the type of number they are prefixed with
+
+	for _, code := range []string{"sfomuseum:callnumber=2005.132.40.8", "sfomuseum:accession_number=he9797.5.c23 s3 1931 c.1 sc env", "2005.132.40.9"} {
+
+		_, err := FindCurrentObjectWithLookup(ctx, lookup, code)
+
+		if !IsNotFound(err) {
+			t.Fatalf("Expected '%s' to not be found, %v", code, err)
+		}
+	}
 }
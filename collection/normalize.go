@@ -0,0 +1,91 @@
+package collection
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NormalizeAccessionNumber returns a normalized copy of 'accno' suitable for comparing accession numbers that have been typed
+// inconsistently. All whitespace is removed, letters are upper-cased and leading zeros are removed from each period-separated
+// segment. For example "2005.132.040.008" and " 2005.132.40.8" are both normalized to "2005.132.40.8".
+func NormalizeAccessionNumber(accno string) string {
+
+	accno = strings.ToUpper(strings.Join(strings.Fields(accno), ""))
+
+	if accno == "" {
+		return ""
+	}
+
+	segments := strings.Split(accno, ".")
+
+	for idx, seg := range segments {
+
+		trimmed := strings.TrimLeft(seg, "0")
+
+		// Retain a single zero for segments which are entirely zeros or where a zero precedes a non-numeric character
+
+		if trimmed != seg && (trimmed == "" || !unicode.IsDigit(rune(trimmed[0]))) {
+			trimmed = "0" + trimmed
+		}
+
+		segments[idx] = trimmed
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// NormalizeCallNumber returns a normalized copy of 'callno' suitable for comparing call numbers that have been typed inconsistently.
+// Letters are upper-cased, runs of whitespace are collapsed in to a single space and whitespace adjacent to periods is removed. For
+// example "HE9797.5 .C23 S3 1931 c. 1 SC ENV" is normalized to "HE9797.5.C23 S3 1931 C.1 SC ENV".
+func NormalizeCallNumber(callno string) string {
+
+	callno = strings.ToUpper(strings.Join(strings.Fields(callno), " "))
+
+	callno = strings.ReplaceAll(callno, " .", ".")
+	callno = strings.ReplaceAll(callno, ". ", ".")
+
+	return callno
+}
+
+// normalizeCode returns the normalized codes to look up for 'code'. Codes prefixed with "sfomuseum:accession_number=" or
+// "sfomuseum:callnumber=" are only normalized as accession numbers or call numbers respectively. Other codes are normalized as both.
+func normalizeCode(code string) []string {
+
+	if v, ok := strings.CutPrefix(code, "sfomuseum:accession_number="); ok {
+		return []string{accessionNumberCode(v)}
+	}
+
+	if v, ok := strings.CutPrefix(code, "sfomuseum:callnumber="); ok {
+		return []string{callNumberCode(v)}
+	}
+
+	return []string{
+		accessionNumberCode(code),
+		callNumberCode(code),
+	}
+}
+
+// accessionNumberCode returns the code used to index objects by their normalized accession number.
+func accessionNumberCode(accno string) string {
+
+	normalized := NormalizeAccessionNumber(accno)
+
+	if normalized == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("normalized:accession_number=%s", normalized)
+}
+
+// callNumberCode returns the code used to index objects by their normalized call number.
+func callNumberCode(callno string) string {
+
+	normalized := NormalizeCallNumber(callno)
+
+	if normalized == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("normalized:callnumber=%s", normalized)
+}
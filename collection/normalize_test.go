@@ -0,0 +1,46 @@
+package collection
+
+import (
+	"testing"
+)
+
+func TestNormalizeAccessionNumber(t *testing.T) {
+
+	tests := map[string]string{
+		"2005.132.40.8":    "2005.132.40.8",
+		"2005.132.040.008": "2005.132.40.8",
+		" 2005.132.40.8 ":  "2005.132.40.8",
+		"2005. 132.40.8":   "2005.132.40.8",
+		"l2010.0201.001a":  "L2010.201.1A",
+		"2010.000.0a":      "2010.0.0A",
+		"":                 "",
+	}
+
+	for input, expected := range tests {
+
+		v := NormalizeAccessionNumber(input)
+
+		if v != expected {
+			t.Fatalf("Unexpected normalization for '%s', expected '%s' but got '%s'", input, expected, v)
+		}
+	}
+}
+
+func TestNormalizeCallNumber(t *testing.T) {
+
+	tests := map[string]string{
+		"HE9797.5.C23 S3 1931 c.1 SC ENV":       "HE9797.5.C23 S3 1931 C.1 SC ENV",
+		"he9797.5.c23 s3 1931 C.1 sc env":       "HE9797.5.C23 S3 1931 C.1 SC ENV",
+		"  HE9797.5 .C23  S3 1931 c. 1 SC ENV ": "HE9797.5.C23 S3 1931 C.1 SC ENV",
+		"":                                      "",
+	}
+
+	for input, expected := range tests {
+
+		v := NormalizeCallNumber(input)
+
+		if v != expected {
+			t.Fatalf("Unexpected normalization for '%s', expected '%s' but got '%s'", input, expected, v)
+		}
+	}
+}
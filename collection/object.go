@@ -14,12 +14,30 @@ type Object struct {
 	AccessionNumber string `json:"sfomuseum:accession_number"`
 	CallNumber      string `json:"sfomuseum:callnumber,omitempty"`
 	IsCurrent       int64  `json:"mz:is_current"`
+	IsDeprecated    int64  `json:"mz:is_deprecated,omitempty"`
+	IsCeased        int64  `json:"mz:is_ceased,omitempty"`
+	IsSuperseded    int64  `json:"mz:is_superseded,omitempty"`
+	// The SFO Museum IDs of the exhibitions that the object has been shown in.
+	ExhibitionIds []int64 `json:"sfomuseum:exhibition_id,omitempty"`
 }
 
 func (w *Object) String() string {
 	return fmt.Sprintf("\"%s\"  %s %d (%d)", w.Name, w.AccessionNumber, w.WhosOnFirstId, w.SFOMuseumId)
 }
 
+// ExistentialFlags returns the existential properties of the Object.
+func (w *Object) ExistentialFlags() *curatorial.ExistentialFlags {
+
+	f := &curatorial.ExistentialFlags{
+		IsCurrent:    w.IsCurrent,
+		IsDeprecated: w.IsDeprecated,
+		IsCeased:     w.IsCeased,
+		IsSuperseded: w.IsSuperseded,
+	}
+
+	return f
+}
+
 // Return the current Object matching 'code'. Multiple matches throw an error.
 func FindCurrentObject(ctx context.Context, code string) (*Object, error) {
 
@@ -66,26 +84,59 @@ func FindObjectsCurrent(ctx context.Context, code string) ([]*Object, error) {
 
 // Returns all Object instances matching 'code' that are marked as current with a custom curatorial.Lookup instance.
 func FindObjectsCurrentWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) ([]*Object, error) {
+	return FindObjects(ctx, lookup, code, curatorial.StateCurrent)
+}
+
+// Returns all Object instances matching 'code' that are in any of the existential states defined by 'states' with a custom
+// curatorial.Lookup instance. If 'states' is empty then all the Object instances matching 'code' are returned.
+func FindObjects(ctx context.Context, lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) ([]*Object, error) {
 
-	rsp, err := lookup.Find(ctx, code)
+	typed_lookup := curatorial.AsTypedLookup[*Object](lookup)
+
+	rsp, err := typed_lookup.Find(ctx, code)
 
 	if err != nil {
+
+		if curatorial.IsUnexpectedType(err) {
+			return nil, err
+		}
+
 		return nil, NotFound{code}
 	}
 
-	current := make([]*Object, 0)
-
-	for _, r := range rsp {
+	matches := make([]*Object, 0)
 
-		g := r.(*Object)
+	for _, g := range rsp {
 
-		// if g.IsCurrent == 0 {
-		if g.IsCurrent != 1 {
+		if !curatorial.MatchesExistentialStates(g, states...) {
 			continue
 		}
 
-		current = append(current, g)
+		matches = append(matches, g)
 	}
 
-	return current, nil
+	return matches, nil
+}
+
+// SearchObjects returns all Object instances whose name matches 'query', ranked by score.
+func SearchObjects(ctx context.Context, query string) ([]*curatorial.SearchResult[*Object], error) {
+
+	lookup, err := NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return SearchObjectsWithLookup(ctx, lookup, query)
+}
+
+// SearchObjectsWithLookup returns all Object instances whose name matches 'query', ranked by score, with a custom curatorial.Lookup instance.
+func SearchObjectsWithLookup(ctx context.Context, lookup curatorial.Lookup, query string) ([]*curatorial.SearchResult[*Object], error) {
+	return curatorial.SearchTypedLookup[*Object](ctx, lookup, query)
+}
+
+// Returns all the Object instances shown in the exhibition whose SFO Museum ID is 'exhibition_id' with a custom curatorial.Lookup instance.
+// If 'states' is not empty only objects in those existential states are returned.
+func FindObjectsInExhibition(ctx context.Context, lookup curatorial.Lookup, exhibition_id int64, states ...curatorial.ExistentialState) ([]*Object, error) {
+	return FindObjects(ctx, lookup, exhibitionCode(exhibition_id), states...)
 }
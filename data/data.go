@@ -4,5 +4,5 @@ import (
 	"embed"
 )
 
-//go:embed *.json
+//go:embed *.json*
 var FS embed.FS
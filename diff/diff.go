@@ -0,0 +1,323 @@
+// Package diff provides methods for comparing two versions of a Who's On First record, for example to review the changes
+// that an editing tool would make before they are written.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+	"github.com/tidwall/gjson"
+)
+
+// The formats that a `Diff` can be written as.
+const (
+	TEXT string = "text"
+	JSON string = "json"
+)
+
+// The kinds of property changes.
+const (
+	ADDED    string = "added"
+	REMOVED  string = "removed"
+	MODIFIED string = "modified"
+)
+
+// Change is a single property-level change between two versions of a record.
+type Change struct {
+	// The name of the property (relative to the record's "properties" dictionary).
+	Property string `json:"property"`
+	// One of `ADDED`, `REMOVED` or `MODIFIED`.
+	Kind string `json:"kind"`
+	// The previous value of the property.
+	Old interface{} `json:"old,omitempty"`
+	// The new value of the property.
+	New interface{} `json:"new,omitempty"`
+}
+
+// GeometrySummary is a summary of the geometries of two versions of a record.
+type GeometrySummary struct {
+	// A boolean value indicating whether the geometry has changed.
+	Changed bool `json:"changed"`
+	// The geometry type of the previous version of the record.
+	OldType string `json:"old_type,omitempty"`
+	// The geometry type of the new version of the record.
+	NewType string `json:"new_type,omitempty"`
+	// The (planar) centroid of the previous geometry. This is nil if there is no previous geometry.
+	OldCentroid *orb.Point `json:"old_centroid,omitempty"`
+	// The (planar) centroid of the new geometry. This is nil if there is no new geometry.
+	NewCentroid *orb.Point `json:"new_centroid,omitempty"`
+	// The bounding box of the previous geometry. This is nil if there is no previous geometry.
+	OldBounds *orb.Bound `json:"old_bounds,omitempty"`
+	// The bounding box of the new geometry. This is nil if there is no new geometry.
+	NewBounds *orb.Bound `json:"new_bounds,omitempty"`
+}
+
+// Diff is the set of changes between two versions of a record.
+type Diff struct {
+	// The Who's On First ID of the (new version of the) record.
+	Id int64 `json:"wof:id"`
+	// The name of the (new version of the) record.
+	Name string `json:"wof:name"`
+	// A boolean value indicating whether the record is being created (there is no previous version).
+	Created bool `json:"created"`
+	// The list of property-level changes, sorted by property name.
+	Changes []*Change `json:"changes"`
+	// A summary of the changes to the record's geometry.
+	Geometry *GeometrySummary `json:"geometry"`
+}
+
+// HasChanges returns a boolean value indicating whether there are any differences between the two versions of a record.
+func (d *Diff) HasChanges() bool {
+	return d.Created || len(d.Changes) > 0 || d.Geometry.Changed
+}
+
+// Compare returns the set of changes between 'old_body' and 'new_body'. If 'old_body' is nil then the record is considered
+// to be newly created and every property will be reported as added.
+func Compare(old_body []byte, new_body []byte) (*Diff, error) {
+
+	old_props, err := decodeProperties(old_body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode previous properties, %w", err)
+	}
+
+	new_props, err := decodeProperties(new_body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode new properties, %w", err)
+	}
+
+	keys := make([]string, 0)
+
+	for k := range old_props {
+		keys = append(keys, k)
+	}
+
+	for k := range new_props {
+
+		if _, exists := old_props[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+
+	slices.Sort(keys)
+
+	changes := make([]*Change, 0)
+
+	for _, k := range keys {
+
+		old_v, old_ok := old_props[k]
+		new_v, new_ok := new_props[k]
+
+		switch {
+		case !old_ok:
+			changes = append(changes, &Change{Property: k, Kind: ADDED, New: new_v})
+		case !new_ok:
+			changes = append(changes, &Change{Property: k, Kind: REMOVED, Old: old_v})
+		case !reflect.DeepEqual(old_v, new_v):
+			changes = append(changes, &Change{Property: k, Kind: MODIFIED, Old: old_v, New: new_v})
+		}
+	}
+
+	geom_summary, err := compareGeometries(old_body, new_body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compare geometries, %w", err)
+	}
+
+	d := &Diff{
+		Id:       gjson.GetBytes(new_body, "properties.wof:id").Int(),
+		Name:     gjson.GetBytes(new_body, "properties.wof:name").String(),
+		Created:  old_body == nil,
+		Changes:  changes,
+		Geometry: geom_summary,
+	}
+
+	return d, nil
+}
+
+// WriteText writes a human-readable representation of 'd' to 'wr'.
+func (d *Diff) WriteText(wr io.Writer) error {
+
+	label := "Modify"
+
+	if d.Created {
+		label = "Create"
+	}
+
+	_, err := fmt.Fprintf(wr, "%s %d (%s)\n", label, d.Id, d.Name)
+
+	if err != nil {
+		return err
+	}
+
+	for _, c := range d.Changes {
+
+		switch c.Kind {
+		case ADDED:
+			_, err = fmt.Fprintf(wr, "  + %s: %s\n", c.Property, formatValue(c.New))
+		case REMOVED:
+			_, err = fmt.Fprintf(wr, "  - %s: %s\n", c.Property, formatValue(c.Old))
+		default:
+			_, err = fmt.Fprintf(wr, "  ~ %s: %s -> %s\n", c.Property, formatValue(c.Old), formatValue(c.New))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	g := d.Geometry
+
+	switch {
+	case !g.Changed:
+		_, err = fmt.Fprintf(wr, "  geometry: unchanged\n")
+	case d.Created:
+		_, err = fmt.Fprintf(wr, "  geometry: %s\n", formatGeometry(g.NewType, g.NewCentroid, g.NewBounds))
+	default:
+		_, err = fmt.Fprintf(wr, "  geometry: %s -> %s\n", formatGeometry(g.OldType, g.OldCentroid, g.OldBounds), formatGeometry(g.NewType, g.NewCentroid, g.NewBounds))
+	}
+
+	return err
+}
+
+// WriteJSON writes a JSON-encoded representation of 'd' to 'wr'.
+func (d *Diff) WriteJSON(wr io.Writer) error {
+	return json.NewEncoder(wr).Encode(d)
+}
+
+// Write writes 'd' to 'wr' in 'format' which is expected to be one of `TEXT` or `JSON`.
+func (d *Diff) Write(wr io.Writer, format string) error {
+
+	switch format {
+	case TEXT:
+		return d.WriteText(wr)
+	case JSON:
+		return d.WriteJSON(wr)
+	default:
+		return fmt.Errorf("Invalid format '%s'", format)
+	}
+}
+
+// decodeProperties returns the "properties" dictionary of 'body'. A nil 'body' returns an empty dictionary.
+func decodeProperties(body []byte) (map[string]interface{}, error) {
+
+	props := make(map[string]interface{})
+
+	if body == nil {
+		return props, nil
+	}
+
+	rsp := gjson.GetBytes(body, "properties")
+
+	if !rsp.Exists() {
+		return props, nil
+	}
+
+	err := json.Unmarshal([]byte(rsp.Raw), &props)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return props, nil
+}
+
+// summarizeGeometry returns the (planar) centroid and the bounding box of 'geom'.
+func summarizeGeometry(geom orb.Geometry) (*orb.Point, *orb.Bound) {
+
+	centroid, _ := planar.CentroidArea(geom)
+	bounds := geom.Bound()
+
+	return &centroid, &bounds
+}
+
+// formatGeometry returns a human-readable summary of a geometry of type 'geom_type' with centroid 'centroid' and bounding
+// box 'bounds'. If 'centroid' is nil there is no geometry.
+func formatGeometry(geom_type string, centroid *orb.Point, bounds *orb.Bound) string {
+
+	if centroid == nil || bounds == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%s centroid %v bounds %v", geom_type, *centroid, *bounds)
+}
+
+// compareGeometries returns a `GeometrySummary` for the geometries of 'old_body' and 'new_body'.
+func compareGeometries(old_body []byte, new_body []byte) (*GeometrySummary, error) {
+
+	old_geom, err := decodeGeometry(old_body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode previous geometry, %w", err)
+	}
+
+	new_geom, err := decodeGeometry(new_body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode new geometry, %w", err)
+	}
+
+	s := &GeometrySummary{}
+
+	if old_geom != nil {
+		s.OldType = old_geom.GeoJSONType()
+		s.OldCentroid, s.OldBounds = summarizeGeometry(old_geom)
+	}
+
+	if new_geom != nil {
+		s.NewType = new_geom.GeoJSONType()
+		s.NewCentroid, s.NewBounds = summarizeGeometry(new_geom)
+	}
+
+	switch {
+	case old_geom == nil && new_geom == nil:
+		s.Changed = false
+	case old_geom == nil || new_geom == nil:
+		s.Changed = true
+	default:
+		s.Changed = !orb.Equal(old_geom, new_geom)
+	}
+
+	return s, nil
+}
+
+// decodeGeometry returns the geometry of 'body'. A nil 'body' or a missing geometry returns nil.
+func decodeGeometry(body []byte) (orb.Geometry, error) {
+
+	if body == nil {
+		return nil, nil
+	}
+
+	rsp := gjson.GetBytes(body, "geometry")
+
+	if !rsp.Exists() || rsp.Type == gjson.Null {
+		return nil, nil
+	}
+
+	g, err := geojson.UnmarshalGeometry([]byte(rsp.Raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Geometry(), nil
+}
+
+// formatValue returns a compact JSON-encoded string for 'v'.
+func formatValue(v interface{}) string {
+
+	enc, err := json.Marshal(v)
+
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(enc)
+}
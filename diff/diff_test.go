@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+
+	old_body := []byte(`{"type":"Feature","properties":{"wof:id":1,"wof:name":"Test","wof:parent_id":-1,"mz:is_current":1},"geometry":{"type":"Point","coordinates":[-122.386,37.616]}}`)
+	new_body := []byte(`{"type":"Feature","properties":{"wof:id":1,"wof:name":"Test","wof:parent_id":1745882459,"wof:hierarchy":[{"gallery_id":1745882459}]},"geometry":{"type":"MultiPoint","coordinates":[[-122.386,37.616],[-122.388,37.618]]}}`)
+
+	d, err := Compare(old_body, new_body)
+
+	if err != nil {
+		t.Fatalf("Failed to compare records, %v", err)
+	}
+
+	if d.Created {
+		t.Fatalf("Expected record to be modified, not created")
+	}
+
+	expected := map[string]string{
+		"mz:is_current": REMOVED,
+		"wof:hierarchy": ADDED,
+		"wof:parent_id": MODIFIED,
+	}
+
+	if len(d.Changes) != len(expected) {
+		t.Fatalf("Unexpected number of changes: %d", len(d.Changes))
+	}
+
+	for _, c := range d.Changes {
+
+		if expected[c.Property] != c.Kind {
+			t.Fatalf("Unexpected change for %s: %s", c.Property, c.Kind)
+		}
+	}
+
+	if !d.Geometry.Changed || d.Geometry.OldType != "Point" || d.Geometry.NewType != "MultiPoint" {
+		t.Fatalf("Unexpected geometry summary: %v", d.Geometry)
+	}
+
+	var buf bytes.Buffer
+
+	err = d.Write(&buf, TEXT)
+
+	if err != nil {
+		t.Fatalf("Failed to write diff, %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "~ wof:parent_id: -1 -> 1745882459") {
+		t.Fatalf("Unexpected text output: %s", buf.String())
+	}
+
+	err = d.Write(&buf, "yaml")
+
+	if err == nil {
+		t.Fatalf("Expected invalid format to fail")
+	}
+}
+
+func TestCompareCreated(t *testing.T) {
+
+	new_body := []byte(`{"type":"Feature","properties":{"wof:id":2,"wof:name":"New"},"geometry":{"type":"Point","coordinates":[0,0]}}`)
+
+	d, err := Compare(nil, new_body)
+
+	if err != nil {
+		t.Fatalf("Failed to compare records, %v", err)
+	}
+
+	if !d.Created || !d.HasChanges() {
+		t.Fatalf("Expected record to be created")
+	}
+
+	if len(d.Changes) != 2 {
+		t.Fatalf("Unexpected number of changes: %d", len(d.Changes))
+	}
+
+	if d.Geometry.OldCentroid != nil || d.Geometry.OldBounds != nil || d.Geometry.NewCentroid == nil || d.Geometry.NewBounds == nil {
+		t.Fatalf("Unexpected geometry summary: %v", d.Geometry)
+	}
+
+	var buf bytes.Buffer
+
+	err = d.Write(&buf, JSON)
+
+	if err != nil {
+		t.Fatalf("Failed to write diff, %v", err)
+	}
+
+	if strings.Contains(buf.String(), "old_centroid") || strings.Contains(buf.String(), "old_bounds") {
+		t.Fatalf("Unexpected previous geometry in JSON output: %s", buf.String())
+	}
+
+	d, err = Compare(new_body, new_body)
+
+	if err != nil {
+		t.Fatalf("Failed to compare records, %v", err)
+	}
+
+	if d.HasChanges() {
+		t.Fatalf("Expected identical records to have no changes")
+	}
+}
@@ -0,0 +1,70 @@
+package curatorial
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnexpectedType is an error returned when a record in a `Lookup` instance is not of the expected type.
+type UnexpectedType struct {
+	expected string
+	value    interface{}
+}
+
+func (e UnexpectedType) Error() string {
+	return fmt.Sprintf("Unexpected type %T, expected %s", e.value, e.expected)
+}
+
+func (e UnexpectedType) String() string {
+	return e.Error()
+}
+
+// IsUnexpectedType returns a boolean value indicating whether 'e' is an `UnexpectedType` error.
+func IsUnexpectedType(e error) bool {
+
+	switch e.(type) {
+	case UnexpectedType, *UnexpectedType:
+		return true
+	default:
+		return false
+	}
+}
+
+// NoPrecompiledData is an error returned when a lookup is unable to find precompiled data.
+type NoPrecompiledData struct{ filename string }
+
+func (e NoPrecompiledData) Error() string {
+	return fmt.Sprintf("No precompiled data available for '%s'", e.filename)
+}
+
+func (e NoPrecompiledData) String() string {
+	return e.Error()
+}
+
+// IsNoPrecompiledData returns a boolean value indicating whether 'e' is, or wraps, a `NoPrecompiledData` error.
+func IsNoPrecompiledData(e error) bool {
+
+	var v NoPrecompiledData
+	return errors.As(e, &v)
+}
+
+// UnexpectedStatus is an error returned when a remote server responds with an unexpected HTTP status code.
+type UnexpectedStatus struct {
+	url    string
+	status int
+}
+
+func (e UnexpectedStatus) Error() string {
+	return fmt.Sprintf("Unexpected status code %d for %s", e.status, e.url)
+}
+
+func (e UnexpectedStatus) String() string {
+	return e.Error()
+}
+
+// IsUnexpectedStatus returns a boolean value indicating whether 'e' is, or wraps, an `UnexpectedStatus` error.
+func IsUnexpectedStatus(e error) bool {
+
+	var v UnexpectedStatus
+	return errors.As(e, &v)
+}
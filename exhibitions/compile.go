@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
 	"github.com/tidwall/gjson"
 	"github.com/whosonfirst/go-whosonfirst-feature/properties"
 	"github.com/whosonfirst/go-whosonfirst-iterate/v3"
@@ -22,13 +24,13 @@ func CompileExhibitionsData(ctx context.Context, iterator_uri string, iterator_s
 	}
 
 	for rec, err := range iter.Iterate(ctx, iterator_sources...) {
-		
+
 		if err != nil {
 			return nil, fmt.Errorf("Failed to iterate sources, %w", err)
 		}
 
 		defer rec.Body.Close()
-		
+
 		select {
 		case <-ctx.Done():
 			break
@@ -65,19 +67,51 @@ func CompileExhibitionsData(ctx context.Context, iterator_uri string, iterator_s
 
 		name_rsp := gjson.GetBytes(body, "properties.wof:name")
 
-		is_current, err := properties.IsCurrent(body)
+		existential_flags, err := curatorial.DeriveExistentialFlags(body)
 
 		if err != nil {
-			return nil, fmt.Errorf("Failed to derive is current for %s, %w", rec.Path, err)
+			return nil, fmt.Errorf("Failed to derive existential flags for %s, %w", rec.Path, err)
 		}
 
 		w := &Exhibition{
 			WhosOnFirstId: wofid_rsp.Int(),
 			SFOMuseumId:   sfomid_rsp.Int(),
 			Name:          name_rsp.String(),
-			IsCurrent:     is_current.Flag(),
+			IsCurrent:     existential_flags.IsCurrent,
+			IsDeprecated:  existential_flags.IsDeprecated,
+			IsCeased:      existential_flags.IsCeased,
+			IsSuperseded:  existential_flags.IsSuperseded,
+		}
+
+		w.Inception = properties.Inception(body)
+		w.Cessation = properties.Cessation(body)
+
+		w.InceptionLower, w.InceptionUpper, err = deriveDateBounds(w.Inception)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive inception dates for %s, %w", rec.Path, err)
+		}
+
+		w.CessationLower, w.CessationUpper, err = deriveDateBounds(w.Cessation)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive cessation dates for %s, %w", rec.Path, err)
+		}
+
+		parent_rsp := gjson.GetBytes(body, "properties.wof:parent_id")
+
+		if parent_rsp.Exists() {
+			w.ParentId = parent_rsp.Int()
 		}
 
+		w.Hierarchy = properties.Hierarchies(body)
+		w.GalleryIds = DeriveGalleryIds(w.ParentId, w.Hierarchy)
+
+		w.Supersedes = properties.Supersedes(body)
+		w.SupersededBy = properties.SupersededBy(body)
+
+		w.PostSecurity = DerivePostSecurity(body)
+
 		www_rsp := gjson.GetBytes(body, "properties.sfomuseum_www:exhibition_id")
 
 		if www_rsp.Exists() {
@@ -89,3 +123,92 @@ func CompileExhibitionsData(ctx context.Context, iterator_uri string, iterator_s
 
 	return lookup, nil
 }
+
+// The keys in a Who's On First hierarchy that may identify the gallery an exhibition was shown in. SFO Museum gallery records
+// have a `wof:placetype` of "enclosure" so they are usually identified by the "enclosure_id" key.
+var gallery_hierarchy_keys = []string{
+	"gallery_id",
+	"enclosure_id",
+}
+
+// DeriveGalleryIds returns the unique list of gallery IDs for an exhibition whose parent is 'parent_id' and whose hierarchies are
+// 'hierarchies'. These are the (non-zero) "gallery_id" and "enclosure_id" values in 'hierarchies'. 'parent_id' is only considered
+// a gallery ID (and listed first) if it is also one of those values, since an exhibition may be parented by a record that is not
+// a gallery (for example a terminal or a boarding area).
+func DeriveGalleryIds(parent_id int64, hierarchies []map[string]int64) []int64 {
+
+	gallery_ids := make([]int64, 0)
+	seen := make(map[int64]bool)
+
+	for _, h := range hierarchies {
+
+		for _, k := range gallery_hierarchy_keys {
+
+			id, ok := h[k]
+
+			if !ok || id <= 0 || seen[id] {
+				continue
+			}
+
+			seen[id] = true
+			gallery_ids = append(gallery_ids, id)
+		}
+	}
+
+	idx := slices.Index(gallery_ids, parent_id)
+
+	if parent_id > 0 && idx > 0 {
+		gallery_ids = slices.Delete(gallery_ids, idx, idx+1)
+		gallery_ids = slices.Insert(gallery_ids, 0, parent_id)
+	}
+
+	return gallery_ids
+}
+
+// DerivePostSecurity returns the `sfomuseum:post_security` property of the Who's On First record 'body' as one of the `POST_SECURITY_` constants.
+func DerivePostSecurity(body []byte) int64 {
+
+	rsp := gjson.GetBytes(body, "properties.sfomuseum:post_security")
+
+	if !rsp.Exists() {
+		return POST_SECURITY_UNKNOWN
+	}
+
+	switch rsp.Type {
+	case gjson.True:
+		return POST_SECURITY_TRUE
+	case gjson.False:
+		return POST_SECURITY_FALSE
+	default:
+
+		switch v := rsp.Int(); v {
+		case POST_SECURITY_TRUE, POST_SECURITY_FALSE, POST_SECURITY_MIXED:
+			return v
+		default:
+			return POST_SECURITY_UNKNOWN
+		}
+	}
+}
+
+// CombinePostSecurity returns the post-security value for an exhibition spanning galleries whose post-security values are 'values'.
+// If any value is unknown then `POST_SECURITY_UNKNOWN` is returned. If the values differ then `POST_SECURITY_MIXED` is returned.
+func CombinePostSecurity(values ...int64) int64 {
+
+	if len(values) == 0 {
+		return POST_SECURITY_UNKNOWN
+	}
+
+	combined := values[0]
+
+	for _, v := range values {
+
+		switch {
+		case v == POST_SECURITY_UNKNOWN:
+			return POST_SECURITY_UNKNOWN
+		case v != combined:
+			combined = POST_SECURITY_MIXED
+		}
+	}
+
+	return combined
+}
@@ -0,0 +1,155 @@
+package exhibitions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const compile_test_feature = `{
+  "type": "Feature",
+  "properties": {
+    "wof:id": 1729792389,
+    "wof:name": "Test Exhibition",
+    "wof:parent_id": -4,
+    "wof:hierarchy": [
+      {"building_id": 1159396329, "gallery_id": 1745882459},
+      {"building_id": 1159396329, "gallery_id": 1745882461},
+      {"building_id": 1159396329, "gallery_id": 1745882459}
+    ],
+    "wof:supersedes": [1729792387],
+    "wof:superseded_by": [],
+    "mz:is_current": 0,
+    "edtf:inception": "2019-06-01",
+    "edtf:cessation": "2019-12-31",
+    "sfomuseum:exhibition_id": 1234,
+    "sfomuseum:post_security": 1
+  },
+  "geometry": {"type": "Point", "coordinates": [-122.386, 37.616]}
+}`
+
+func TestCompileExhibitionsData(t *testing.T) {
+
+	ctx := context.Background()
+
+	root := t.TempDir()
+	path := filepath.Join(root, "1729792389.geojson")
+
+	err := os.WriteFile(path, []byte(compile_test_feature), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write test feature, %v", err)
+	}
+
+	lookup, err := CompileExhibitionsData(ctx, "directory://", root)
+
+	if err != nil {
+		t.Fatalf("Failed to compile exhibitions data, %v", err)
+	}
+
+	if len(lookup) != 1 {
+		t.Fatalf("Expected 1 exhibition, got %d", len(lookup))
+	}
+
+	e := lookup[0]
+
+	if e.Inception != "2019-06-01" || e.Cessation != "2019-12-31" {
+		t.Fatalf("Unexpected dates: %s - %s", e.Inception, e.Cessation)
+	}
+
+	if e.InceptionLower != "2019-06-01" || e.CessationUpper != "2019-12-31" {
+		t.Fatalf("Unexpected date bounds: %s - %s", e.InceptionLower, e.CessationUpper)
+	}
+
+	if e.ParentId != -4 {
+		t.Fatalf("Unexpected parent ID: %d", e.ParentId)
+	}
+
+	if len(e.Hierarchy) != 3 {
+		t.Fatalf("Unexpected hierarchy count: %d", len(e.Hierarchy))
+	}
+
+	if e.PostSecurity != 1 {
+		t.Fatalf("Unexpected post security: %d", e.PostSecurity)
+	}
+
+	if len(e.Supersedes) != 1 || e.Supersedes[0] != 1729792387 {
+		t.Fatalf("Unexpected supersedes: %v", e.Supersedes)
+	}
+
+	if len(e.SupersededBy) != 0 {
+		t.Fatalf("Unexpected superseded by: %v", e.SupersededBy)
+	}
+
+	if len(e.GalleryIds) != 2 || e.GalleryIds[0] != 1745882459 || e.GalleryIds[1] != 1745882461 {
+		t.Fatalf("Unexpected gallery IDs: %v", e.GalleryIds)
+	}
+}
+
+func TestCombinePostSecurity(t *testing.T) {
+
+	tests := []struct {
+		Values   []int64
+		Expected int64
+	}{
+		{[]int64{POST_SECURITY_TRUE, POST_SECURITY_TRUE}, POST_SECURITY_TRUE},
+		{[]int64{POST_SECURITY_FALSE, POST_SECURITY_FALSE}, POST_SECURITY_FALSE},
+		{[]int64{POST_SECURITY_TRUE, POST_SECURITY_FALSE}, POST_SECURITY_MIXED},
+		{[]int64{POST_SECURITY_TRUE, POST_SECURITY_UNKNOWN}, POST_SECURITY_UNKNOWN},
+		{[]int64{}, POST_SECURITY_UNKNOWN},
+	}
+
+	for _, test := range tests {
+
+		v := CombinePostSecurity(test.Values...)
+
+		if v != test.Expected {
+			t.Fatalf("Unexpected post security value for %v. Got %d but expected %d", test.Values, v, test.Expected)
+		}
+	}
+}
+
+func TestDeriveGalleryIds(t *testing.T) {
+
+	hierarchies := []map[string]int64{
+		{"building_id": 10, "enclosure_id": 1},
+		{"building_id": 10, "gallery_id": 2},
+		{"building_id": 10, "enclosure_id": 1},
+	}
+
+	ids := DeriveGalleryIds(1, hierarchies)
+
+	if !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("Unexpected gallery IDs: %v", ids)
+	}
+
+	ids = DeriveGalleryIds(-4, hierarchies)
+
+	if !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("Unexpected gallery IDs for multiple galleries: %v", ids)
+	}
+
+	ids = DeriveGalleryIds(2, hierarchies)
+
+	if !slices.Equal(ids, []int64{2, 1}) {
+		t.Fatalf("Unexpected gallery IDs for gallery parent: %v", ids)
+	}
+
+	// Parents that are not galleries (for example a terminal) are not gallery IDs
+
+	ids = DeriveGalleryIds(3, []map[string]int64{})
+
+	if !slices.Equal(ids, []int64{}) {
+		t.Fatalf("Unexpected gallery IDs without hierarchies: %v", ids)
+	}
+
+	ids = DeriveGalleryIds(20, []map[string]int64{
+		{"building_id": 10, "wing_id": 20},
+	})
+
+	if !slices.Equal(ids, []int64{}) {
+		t.Fatalf("Unexpected gallery IDs for non-gallery parent: %v", ids)
+	}
+}
@@ -0,0 +1,277 @@
+package exhibitions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-edtf"
+	"github.com/sfomuseum/go-edtf/parser"
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+)
+
+// The layout used for the (derived) `date:` properties of an Exhibition.
+const DATE_LAYOUT string = "2006-01-02"
+
+// DateMatch defines how the uncertainty in an exhibition's EDTF dates is handled when comparing them to a date or a range of dates.
+type DateMatch int
+
+const (
+	// Match exhibitions that were possibly on view using the earliest possible inception date and the latest possible
+	// cessation date. Unknown dates are treated as open-ended but exhibitions whose dates are both unknown never match.
+	MatchPossible DateMatch = iota
+	// Match exhibitions that were definitely on view using the latest possible inception date and the earliest possible
+	// cessation date. Exhibitions with unknown dates never match.
+	MatchCertain
+)
+
+// deriveDateBounds returns the earliest and latest possible dates, formatted using `DATE_LAYOUT`, for 'edtf_str'. Open and
+// unknown EDTF strings return empty values.
+func deriveDateBounds(edtf_str string) (string, string, error) {
+
+	if edtf.IsOpen(edtf_str) || edtf.IsUnknown(edtf_str) {
+		return "", "", nil
+	}
+
+	d, err := parser.ParseString(edtf_str)
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse '%s', %w", edtf_str, err)
+	}
+
+	lower, err := d.Lower()
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to derive lower bound for '%s', %w", edtf_str, err)
+	}
+
+	upper, err := d.Upper()
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to derive upper bound for '%s', %w", edtf_str, err)
+	}
+
+	return lower.Format(DATE_LAYOUT), upper.Format(DATE_LAYOUT), nil
+}
+
+// viewingWindow returns the first and last days (formatted using `DATE_LAYOUT`) that the exhibition was on view, according to 'm'.
+// Empty values indicate that the window is open-ended. The final boolean value is false if the window can not be determined.
+func (w *Exhibition) viewingWindow(m DateMatch) (string, string, bool) {
+
+	switch m {
+	case MatchCertain:
+
+		if edtf.IsUnknown(w.Inception) || edtf.IsUnknown(w.Cessation) {
+			return "", "", false
+		}
+
+		return w.InceptionUpper, w.CessationLower, true
+
+	default:
+
+		if edtf.IsUnknown(w.Inception) && edtf.IsUnknown(w.Cessation) {
+			return "", "", false
+		}
+
+		return w.InceptionLower, w.CessationUpper, true
+	}
+}
+
+// OnViewAt returns a boolean value indicating whether the exhibition was on view on the day of 't'.
+func (w *Exhibition) OnViewAt(t time.Time, m DateMatch) bool {
+	return w.OnViewBetween(t, t, m)
+}
+
+// OnViewBetween returns a boolean value indicating whether the exhibition was on view at any time between the days of 'start' and 'end' (inclusive).
+func (w *Exhibition) OnViewBetween(start time.Time, end time.Time, m DateMatch) bool {
+
+	first, last, ok := w.viewingWindow(m)
+
+	if !ok {
+		return false
+	}
+
+	if first != "" && first > end.Format(DATE_LAYOUT) {
+		return false
+	}
+
+	if last != "" && last < start.Format(DATE_LAYOUT) {
+		return false
+	}
+
+	return true
+}
+
+// OpensBetween returns a boolean value indicating whether the exhibition (possibly) opened between the days of 'start' and 'end' (inclusive).
+func (w *Exhibition) OpensBetween(start time.Time, end time.Time) bool {
+	return overlapsDays(w.InceptionLower, w.InceptionUpper, start, end)
+}
+
+// ClosesBetween returns a boolean value indicating whether the exhibition (possibly) closed between the days of 'start' and 'end' (inclusive).
+func (w *Exhibition) ClosesBetween(start time.Time, end time.Time) bool {
+	return overlapsDays(w.CessationLower, w.CessationUpper, start, end)
+}
+
+// overlapsDays returns a boolean value indicating whether the days 'lower' and 'upper' overlap the days of 'start' and 'end'. Empty
+// values for 'lower' or 'upper' never match.
+func overlapsDays(lower string, upper string, start time.Time, end time.Time) bool {
+
+	if lower == "" || upper == "" {
+		return false
+	}
+
+	return lower <= end.Format(DATE_LAYOUT) && upper >= start.Format(DATE_LAYOUT)
+}
+
+// Returns all the Exhibition instances that were on view on the day of 't'.
+func FindExhibitionsOnView(ctx context.Context, t time.Time, m DateMatch) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return FindExhibitionsOnViewWithLookup(ctx, lookup, t, m)
+}
+
+// Returns all the Exhibition instances that were on view on the day of 't' with a custom curatorial.Lookup instance.
+func FindExhibitionsOnViewWithLookup(ctx context.Context, lookup curatorial.Lookup, t time.Time, m DateMatch) ([]*Exhibition, error) {
+	return FindExhibitionsOverlappingWithLookup(ctx, lookup, t, t, m)
+}
+
+// Returns all the Exhibition instances that were on view at any time between the days of 'start' and 'end' (inclusive).
+func FindExhibitionsOverlapping(ctx context.Context, start time.Time, end time.Time, m DateMatch) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return FindExhibitionsOverlappingWithLookup(ctx, lookup, start, end, m)
+}
+
+// Returns all the Exhibition instances that were on view at any time between the days of 'start' and 'end' (inclusive) with a
+// custom curatorial.Lookup instance. Results are sorted by inception date.
+func FindExhibitionsOverlappingWithLookup(ctx context.Context, lookup curatorial.Lookup, start time.Time, end time.Time, m DateMatch) ([]*Exhibition, error) {
+
+	matches, err := filterExhibitionsWithLookup(ctx, lookup, func(w *Exhibition) bool {
+		return w.OnViewBetween(start, end, m)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sortExhibitionsByDate(matches, func(w *Exhibition) string { return w.InceptionLower })
+	return matches, nil
+}
+
+// Returns all the Exhibition instances that (possibly) open after the day of 't' and within 'd' of 't'.
+func FindUpcomingExhibitions(ctx context.Context, t time.Time, d time.Duration) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return FindUpcomingExhibitionsWithLookup(ctx, lookup, t, d)
+}
+
+// Returns all the Exhibition instances that (possibly) open after the day of 't' and within 'd' of 't' with a custom
+// curatorial.Lookup instance. Results are sorted by inception date.
+func FindUpcomingExhibitionsWithLookup(ctx context.Context, lookup curatorial.Lookup, t time.Time, d time.Duration) ([]*Exhibition, error) {
+
+	start := t.AddDate(0, 0, 1)
+	end := t.Add(d)
+
+	matches, err := filterExhibitionsWithLookup(ctx, lookup, func(w *Exhibition) bool {
+		return w.OpensBetween(start, end)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sortExhibitionsByDate(matches, func(w *Exhibition) string { return w.InceptionLower })
+	return matches, nil
+}
+
+// Returns all the Exhibition instances that are (possibly) on view on the day of 't' and that (possibly) close within 'd' of 't'.
+func FindClosingExhibitions(ctx context.Context, t time.Time, d time.Duration) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return FindClosingExhibitionsWithLookup(ctx, lookup, t, d)
+}
+
+// Returns all the Exhibition instances that are (possibly) on view on the day of 't' and that (possibly) close within 'd' of 't'
+// with a custom curatorial.Lookup instance. Results are sorted by cessation date.
+func FindClosingExhibitionsWithLookup(ctx context.Context, lookup curatorial.Lookup, t time.Time, d time.Duration) ([]*Exhibition, error) {
+
+	end := t.Add(d)
+
+	matches, err := filterExhibitionsWithLookup(ctx, lookup, func(w *Exhibition) bool {
+		return w.OnViewAt(t, MatchPossible) && w.ClosesBetween(t, end)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sortExhibitionsByDate(matches, func(w *Exhibition) string { return w.CessationLower })
+	return matches, nil
+}
+
+// filterExhibitionsWithLookup returns all the (non-deprecated) Exhibition instances in 'lookup' for which 'include' returns true.
+func filterExhibitionsWithLookup(ctx context.Context, lookup curatorial.Lookup, include func(*Exhibition) bool) ([]*Exhibition, error) {
+
+	typed_lookup := curatorial.AsTypedLookup[*Exhibition](lookup)
+
+	matches := make([]*Exhibition, 0)
+
+	for w, err := range typed_lookup.Iterate(ctx) {
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to iterate exhibitions, %w", err)
+		}
+
+		if w.IsDeprecated == 1 {
+			continue
+		}
+
+		if include(w) {
+			matches = append(matches, w)
+		}
+	}
+
+	return matches, nil
+}
+
+// sortExhibitionsByDate sorts 'exhibitions' by the date returned by 'date_func' and then by Who's On First ID.
+func sortExhibitionsByDate(exhibitions []*Exhibition, date_func func(*Exhibition) string) {
+
+	slices.SortStableFunc(exhibitions, func(a *Exhibition, b *Exhibition) int {
+
+		if c := strings.Compare(date_func(a), date_func(b)); c != 0 {
+			return c
+		}
+
+		switch {
+		case a.WhosOnFirstId < b.WhosOnFirstId:
+			return -1
+		case a.WhosOnFirstId > b.WhosOnFirstId:
+			return 1
+		default:
+			return 0
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package exhibitions
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newDatesTestExhibitions(t *testing.T) []*Exhibition {
+
+	t.Helper()
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1, Name: "Spring", Inception: "2019-03-01", Cessation: "2019-06-15"},
+		&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 2, Name: "Summer", Inception: "2019-06", Cessation: "2019-09-30"},
+		&Exhibition{WhosOnFirstId: 3, SFOMuseumId: 3, Name: "Ongoing", Inception: "2019-05-01", Cessation: ".."},
+		&Exhibition{WhosOnFirstId: 4, SFOMuseumId: 4, Name: "Unknown", Inception: "2019-01-01", Cessation: ""},
+		&Exhibition{WhosOnFirstId: 5, SFOMuseumId: 5, Name: "Deprecated", Inception: "2019-01-01", Cessation: "2019-12-31", IsDeprecated: 1},
+		&Exhibition{WhosOnFirstId: 7, SFOMuseumId: 7, Name: "Undated"},
+		&Exhibition{WhosOnFirstId: 6, SFOMuseumId: 6, Name: "Autumn", Inception: "2019-10-01", Cessation: "2019-12-31"},
+	}
+
+	for _, w := range exhibitions_list {
+
+		var err error
+
+		w.InceptionLower, w.InceptionUpper, err = deriveDateBounds(w.Inception)
+
+		if err != nil {
+			t.Fatalf("Failed to derive inception dates for %s, %v", w, err)
+		}
+
+		w.CessationLower, w.CessationUpper, err = deriveDateBounds(w.Cessation)
+
+		if err != nil {
+			t.Fatalf("Failed to derive cessation dates for %s, %v", w, err)
+		}
+	}
+
+	return exhibitions_list
+}
+
+func exhibitionIds(exhibitions_list []*Exhibition) []int64 {
+
+	ids := make([]int64, len(exhibitions_list))
+
+	for idx, w := range exhibitions_list {
+		ids[idx] = w.WhosOnFirstId
+	}
+
+	return ids
+}
+
+func TestDeriveDateBounds(t *testing.T) {
+
+	tests := map[string][2]string{
+		"2019-06-01": [2]string{"2019-06-01", "2019-06-01"},
+		"2019-06":    [2]string{"2019-06-01", "2019-06-30"},
+		"2019~":      [2]string{"2019-01-01", "2019-12-31"},
+		"..":         [2]string{"", ""},
+		"uuuu":       [2]string{"", ""},
+		"":           [2]string{"", ""},
+	}
+
+	for edtf_str, expected := range tests {
+
+		lower, upper, err := deriveDateBounds(edtf_str)
+
+		if err != nil {
+			t.Fatalf("Failed to derive bounds for '%s', %v", edtf_str, err)
+		}
+
+		if lower != expected[0] || upper != expected[1] {
+			t.Fatalf("Unexpected bounds for '%s': %s, %s", edtf_str, lower, upper)
+		}
+	}
+
+	_, _, err := deriveDateBounds("not a date")
+
+	if err == nil {
+		t.Fatalf("Expected invalid EDTF string to fail")
+	}
+}
+
+func TestFindExhibitionsOnView(t *testing.T) {
+
+	ctx := context.Background()
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, newDatesTestExhibitions(t)))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	tests := []struct {
+		Date     string
+		Match    DateMatch
+		Expected []int64
+	}{
+		{"2019-06-01", MatchPossible, []int64{4, 1, 3, 2}},
+		{"2019-06-01", MatchCertain, []int64{1, 3}},
+		{"2019-06-30", MatchCertain, []int64{3, 2}},
+		{"2020-01-01", MatchPossible, []int64{4, 3}},
+		{"2018-01-01", MatchPossible, []int64{}},
+	}
+
+	for _, test := range tests {
+
+		d, err := time.Parse(DATE_LAYOUT, test.Date)
+
+		if err != nil {
+			t.Fatalf("Failed to parse %s, %v", test.Date, err)
+		}
+
+		rsp, err := FindExhibitionsOnViewWithLookup(ctx, lookup, d, test.Match)
+
+		if err != nil {
+			t.Fatalf("Failed to find exhibitions on view for %s, %v", test.Date, err)
+		}
+
+		ids := exhibitionIds(rsp)
+
+		if !slices.Equal(ids, test.Expected) {
+			t.Fatalf("Unexpected exhibitions on view for %s (%d). Got %v but expected %v", test.Date, test.Match, ids, test.Expected)
+		}
+	}
+}
+
+func TestFindExhibitionsOverlapping(t *testing.T) {
+
+	ctx := context.Background()
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, newDatesTestExhibitions(t)))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	start, _ := time.Parse(DATE_LAYOUT, "2019-09-15")
+	end, _ := time.Parse(DATE_LAYOUT, "2019-10-15")
+
+	rsp, err := FindExhibitionsOverlappingWithLookup(ctx, lookup, start, end, MatchCertain)
+
+	if err != nil {
+		t.Fatalf("Failed to find overlapping exhibitions, %v", err)
+	}
+
+	ids := exhibitionIds(rsp)
+	expected := []int64{3, 2, 6}
+
+	if !slices.Equal(ids, expected) {
+		t.Fatalf("Unexpected overlapping exhibitions. Got %v but expected %v", ids, expected)
+	}
+}
+
+func TestFindUpcomingAndClosingExhibitions(t *testing.T) {
+
+	ctx := context.Background()
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, newDatesTestExhibitions(t)))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	now, _ := time.Parse(DATE_LAYOUT, "2019-09-15")
+	month := 30 * 24 * time.Hour
+
+	upcoming, err := FindUpcomingExhibitionsWithLookup(ctx, lookup, now, month)
+
+	if err != nil {
+		t.Fatalf("Failed to find upcoming exhibitions, %v", err)
+	}
+
+	ids := exhibitionIds(upcoming)
+
+	if !slices.Equal(ids, []int64{6}) {
+		t.Fatalf("Unexpected upcoming exhibitions: %v", ids)
+	}
+
+	closing, err := FindClosingExhibitionsWithLookup(ctx, lookup, now, month)
+
+	if err != nil {
+		t.Fatalf("Failed to find closing exhibitions, %v", err)
+	}
+
+	ids = exhibitionIds(closing)
+
+	if !slices.Equal(ids, []int64{2}) {
+		t.Fatalf("Unexpected closing exhibitions: %v", ids)
+	}
+}
@@ -0,0 +1,182 @@
+package edit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-reader/v2"
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+	wof_reader "github.com/whosonfirst/go-whosonfirst-reader/v2"
+)
+
+// AssignGalleriesOptions defines configuration details for assigning galleries to an exhibition.
+type AssignGalleriesOptions struct {
+	// The type of geometry to assign to exhibitions spanning multiple galleries. Valid options are `GEOMETRY_MULTIPOINT` (the
+	// centroids of each gallery) and `GEOMETRY_MULTIPOLYGON` (the union of the footprints of each gallery, with the walls shared by
+	// adjacent galleries dissolved, as a MultiPolygon). Default is `GEOMETRY_MULTIPOINT`.
+	MultiGalleryGeometry string
+}
+
+// DefaultAssignGalleriesOptions returns an `AssignGalleriesOptions` instance with default values.
+func DefaultAssignGalleriesOptions() *AssignGalleriesOptions {
+
+	opts := &AssignGalleriesOptions{
+		MultiGalleryGeometry: GEOMETRY_MULTIPOINT,
+	}
+
+	return opts
+}
+
+// AssignGalleries updates the `wof:parent_id`, `wof:hierarchy`, `sfomuseum:post_security` and geometry properties of the exhibition
+// record 'exh_f' using the gallery records in 'galleries' (each of which is validated first). If 'galleries' is empty the exhibition's
+// parent is set to -1 and its hierarchy is removed. If there are multiple galleries the parent is set to -4 and the geometry is derived
+// according to 'opts'. It returns a boolean value indicating whether the record was changed and the updated record.
+func AssignGalleries(ctx context.Context, exh_f []byte, galleries [][]byte, opts *AssignGalleriesOptions) (bool, []byte, error) {
+
+	if opts == nil {
+		opts = DefaultAssignGalleriesOptions()
+	}
+
+	multi_gallery_geometry := opts.MultiGalleryGeometry
+
+	switch multi_gallery_geometry {
+	case "":
+		multi_gallery_geometry = GEOMETRY_MULTIPOINT
+	case GEOMETRY_MULTIPOINT, GEOMETRY_MULTIPOLYGON:
+		// pass
+	default:
+		return false, nil, fmt.Errorf("Invalid multi-gallery geometry '%s'", multi_gallery_geometry)
+	}
+
+	for _, gal_f := range galleries {
+
+		err := ValidateGallery(gal_f, exh_f)
+
+		if err != nil {
+			return false, nil, fmt.Errorf("Invalid gallery %d, %w", gjson.GetBytes(gal_f, "properties.wof:id").Int(), err)
+		}
+	}
+
+	err := ValidateGalleryBuildings(galleries)
+
+	if err != nil {
+		return false, nil, fmt.Errorf("Invalid galleries, %w", err)
+	}
+
+	updates := make(map[string]interface{})
+
+	switch len(galleries) {
+	case 0:
+		updates["properties.wof:parent_id"] = -1
+		updates["properties.wof:hierarchy"] = make([]map[string]int64, 0)
+	case 1:
+		updates["properties.wof:parent_id"] = gjson.GetBytes(galleries[0], "properties.wof:id").Int()
+		updates["properties.wof:hierarchy"] = gjson.GetBytes(galleries[0], "properties.wof:hierarchy").Value()
+		updates["properties.sfomuseum:post_security"] = gjson.GetBytes(galleries[0], "properties.sfomuseum:post_security").Value()
+		updates["geometry"] = gjson.GetBytes(galleries[0], "geometry").Value()
+	default:
+
+		hiers := make([]map[string]interface{}, 0)
+		post_security := make([]int64, len(galleries))
+
+		for idx, body := range galleries {
+
+			for _, r := range gjson.GetBytes(body, "properties.wof:hierarchy").Array() {
+
+				h, ok := r.Value().(map[string]interface{})
+
+				if !ok {
+					return false, nil, fmt.Errorf("Invalid hierarchy for gallery")
+				}
+
+				hiers = append(hiers, h)
+			}
+
+			post_security[idx] = exhibitions.DerivePostSecurity(body)
+		}
+
+		updates["properties.wof:parent_id"] = -4
+		updates["properties.wof:hierarchy"] = hiers
+		updates["properties.sfomuseum:post_security"] = exhibitions.CombinePostSecurity(post_security...)
+
+		switch multi_gallery_geometry {
+		case GEOMETRY_MULTIPOLYGON:
+
+			mp, err := galleryFootprints(galleries)
+
+			if err != nil {
+				return false, nil, fmt.Errorf("Failed to derive gallery footprints, %w", err)
+			}
+
+			for k, v := range multiPolygonUpdates(mp) {
+				updates[k] = v
+			}
+
+		default:
+
+			coords := make([][]float64, 0)
+
+			for _, body := range galleries {
+
+				pt, _, err := properties.Centroid(body)
+
+				if err != nil {
+					return false, nil, fmt.Errorf("Failed to derive centroid for gallery, %w", err)
+				}
+
+				coords = append(coords, []float64{pt.X(), pt.Y()})
+			}
+
+			updates["geometry.type"] = "MultiPoint"
+			updates["geometry.coordinates"] = coords
+		}
+	}
+
+	has_updates, new_exh_f, err := export.AssignPropertiesIfChanged(ctx, exh_f, updates)
+
+	if err != nil {
+		return false, nil, fmt.Errorf("Failed to assign properties, %w", err)
+	}
+
+	return has_updates, new_exh_f, nil
+}
+
+// LoadGallery loads the gallery record 'gallery_id' from 'arch_r'. If 'follow_superseded' is true and the record has been
+// superseded then its `wof:superseded_by` chain is followed until a record that has not been superseded is found.
+func LoadGallery(ctx context.Context, arch_r reader.Reader, gallery_id int64, follow_superseded bool) ([]byte, error) {
+
+	seen := make(map[int64]bool)
+	current_id := gallery_id
+
+	for {
+
+		if seen[current_id] {
+			return nil, fmt.Errorf("Cycle detected following wof:superseded_by for gallery %d", gallery_id)
+		}
+
+		seen[current_id] = true
+
+		gal_f, err := wof_reader.LoadBytes(ctx, arch_r, current_id)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load gallery record %d, %w", current_id, err)
+		}
+
+		superseded_by := properties.SupersededBy(gal_f)
+
+		if !follow_superseded || len(superseded_by) == 0 {
+			return gal_f, nil
+		}
+
+		if len(superseded_by) > 1 {
+			return nil, fmt.Errorf("Gallery %d is superseded by multiple records (%v), unable to determine which one to use", current_id, superseded_by)
+		}
+
+		slog.Info("Gallery has been superseded, using superseding record instead", "gallery", current_id, "superseded_by", superseded_by[0])
+		current_id = superseded_by[0]
+	}
+}
@@ -0,0 +1,251 @@
+package edit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+)
+
+func TestAssignGalleries(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+
+	gallery_a := newGallery(t, 1, 10, 0.0, 0.0)
+	gallery_b := newGallery(t, 2, 10, 2.0, 0.0)
+
+	// One gallery
+
+	has_updates, new_f, err := AssignGalleries(ctx, exh_f, [][]byte{gallery_a}, nil)
+
+	if err != nil {
+		t.Fatalf("Failed to assign single gallery, %v", err)
+	}
+
+	if !has_updates {
+		t.Fatalf("Expected single gallery to update exhibition")
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:parent_id").Int() != 1 {
+		t.Fatalf("Unexpected parent ID for single gallery: %s", gjson.GetBytes(new_f, "properties.wof:parent_id").Raw)
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:hierarchy.0.building_id").Int() != 10 {
+		t.Fatalf("Unexpected hierarchy for single gallery: %s", gjson.GetBytes(new_f, "properties.wof:hierarchy").Raw)
+	}
+
+	if gjson.GetBytes(new_f, "geometry.type").String() != "Polygon" {
+		t.Fatalf("Unexpected geometry for single gallery: %s", gjson.GetBytes(new_f, "geometry.type").String())
+	}
+
+	// Multiple galleries, as a MultiPoint
+
+	_, new_f, err = AssignGalleries(ctx, exh_f, [][]byte{gallery_a, gallery_b}, nil)
+
+	if err != nil {
+		t.Fatalf("Failed to assign multiple galleries, %v", err)
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:parent_id").Int() != -4 {
+		t.Fatalf("Unexpected parent ID for multiple galleries: %s", gjson.GetBytes(new_f, "properties.wof:parent_id").Raw)
+	}
+
+	if len(gjson.GetBytes(new_f, "properties.wof:hierarchy").Array()) != 2 {
+		t.Fatalf("Unexpected hierarchy for multiple galleries: %s", gjson.GetBytes(new_f, "properties.wof:hierarchy").Raw)
+	}
+
+	if gjson.GetBytes(new_f, "properties.sfomuseum:post_security").Int() != 1 {
+		t.Fatalf("Unexpected post security for multiple galleries: %s", gjson.GetBytes(new_f, "properties.sfomuseum:post_security").Raw)
+	}
+
+	if gjson.GetBytes(new_f, "geometry.type").String() != "MultiPoint" || len(gjson.GetBytes(new_f, "geometry.coordinates").Array()) != 2 {
+		t.Fatalf("Unexpected geometry for multiple galleries: %s", gjson.GetBytes(new_f, "geometry").Raw)
+	}
+
+	// Multiple galleries, as a MultiPolygon
+
+	opts := &AssignGalleriesOptions{
+		MultiGalleryGeometry: GEOMETRY_MULTIPOLYGON,
+	}
+
+	_, new_f, err = AssignGalleries(ctx, exh_f, [][]byte{gallery_a, gallery_b}, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to assign multiple galleries as MultiPolygon, %v", err)
+	}
+
+	if gjson.GetBytes(new_f, "geometry.type").String() != "MultiPolygon" {
+		t.Fatalf("Unexpected geometry for multiple galleries: %s", gjson.GetBytes(new_f, "geometry.type").String())
+	}
+
+	if gjson.GetBytes(new_f, "properties.geom:bbox").String() != "0,0,3,1" {
+		t.Fatalf("Unexpected bounding box for multiple galleries: %s", gjson.GetBytes(new_f, "properties.geom:bbox").String())
+	}
+
+	// No galleries
+
+	_, new_f, err = AssignGalleries(ctx, exh_f, [][]byte{}, nil)
+
+	if err != nil {
+		t.Fatalf("Failed to assign no galleries, %v", err)
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:parent_id").Int() != -1 {
+		t.Fatalf("Unexpected parent ID for no galleries: %s", gjson.GetBytes(new_f, "properties.wof:parent_id").Raw)
+	}
+}
+
+func TestAssignGalleriesInvalid(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+
+	gallery_a := newGallery(t, 1, 10, 0.0, 0.0)
+	gallery_other := newGallery(t, 3, 11, 0.0, 0.0)
+
+	deprecated, err := export.AssignProperties(ctx, newGallery(t, 4, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.edtf:deprecated": "2010-01-01",
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create deprecated gallery, %v", err)
+	}
+
+	superseded, err := export.AssignProperties(ctx, newGallery(t, 5, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.wof:superseded_by": []int64{6},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create superseded gallery, %v", err)
+	}
+
+	closed, err := export.AssignProperties(ctx, newGallery(t, 7, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.mz:is_current":  0,
+		"properties.edtf:cessation": "2010-01-01",
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create closed gallery, %v", err)
+	}
+
+	not_gallery, err := export.AssignProperties(ctx, newGallery(t, 8, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.sfomuseum:placetype": "building",
+		"properties.wof:placetype":       "building",
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create building, %v", err)
+	}
+
+	tests := map[string][][]byte{
+		"different buildings": {gallery_a, gallery_other},
+		"deprecated":          {deprecated},
+		"superseded":          {superseded},
+		"closed":              {closed},
+		"not a gallery":       {not_gallery},
+	}
+
+	for label, galleries := range tests {
+
+		_, _, err := AssignGalleries(ctx, exh_f, galleries, nil)
+
+		if err == nil {
+			t.Fatalf("Expected assigning %s gallery to fail", label)
+		}
+	}
+
+	_, _, err = AssignGalleries(ctx, exh_f, [][]byte{superseded}, nil)
+
+	if err == nil || !strings.Contains(err.Error(), "superseded by 6") || !strings.Contains(err.Error(), "-follow-superseded") {
+		t.Fatalf("Expected superseded gallery error to include successor and -follow-superseded hint, got %v", err)
+	}
+
+	opts := &AssignGalleriesOptions{
+		MultiGalleryGeometry: "polygon",
+	}
+
+	_, _, err = AssignGalleries(ctx, exh_f, [][]byte{gallery_a}, opts)
+
+	if err == nil {
+		t.Fatalf("Expected invalid multi-gallery geometry to fail")
+	}
+}
+
+func TestAssignGalleriesIndex(t *testing.T) {
+
+	ctx := context.Background()
+
+	gallery_a := newGallery(t, 1, 10, 0.0, 0.0)
+	gallery_b := newGallery(t, 2, 10, 2.0, 0.0)
+
+	assignments := map[int64][][]byte{
+		100: {gallery_a},
+		101: {gallery_a, gallery_b},
+		102: {gallery_b},
+	}
+
+	root := t.TempDir()
+
+	for exh_id, galleries := range assignments {
+
+		_, exh_f, err := AssignGalleries(ctx, newExhibition(t, exh_id, "2019-06-01", "2020-01-05"), galleries, nil)
+
+		if err != nil {
+			t.Fatalf("Failed to assign galleries to %d, %v", exh_id, err)
+		}
+
+		err = os.WriteFile(filepath.Join(root, fmt.Sprintf("%d.geojson", exh_id)), exh_f, 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to write %d, %v", exh_id, err)
+		}
+	}
+
+	exhibitions_list, err := exhibitions.CompileExhibitionsData(ctx, "directory://", root)
+
+	if err != nil {
+		t.Fatalf("Failed to compile exhibitions data, %v", err)
+	}
+
+	lookup, err := exhibitions.NewLookupWithLookupFunc(ctx, exhibitions.NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	tests := map[int64][]int64{
+		1: {100, 101},
+		2: {101, 102},
+	}
+
+	for gallery_id, expected := range tests {
+
+		rsp, err := exhibitions.ExhibitionsInGalleryWithLookup(ctx, lookup, gallery_id)
+
+		if err != nil {
+			t.Fatalf("Failed to find exhibitions in gallery %d, %v", gallery_id, err)
+		}
+
+		ids := make([]int64, len(rsp))
+
+		for idx, exh := range rsp {
+			ids[idx] = exh.WhosOnFirstId
+		}
+
+		slices.Sort(ids)
+
+		if !slices.Equal(ids, expected) {
+			t.Fatalf("Unexpected exhibitions in gallery %d: %v", gallery_id, ids)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package edit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-edtf"
+	"github.com/sfomuseum/go-edtf/parser"
+)
+
+// startsAfter returns a boolean value indicating whether the EDTF date 'inception' falls after the EDTF date 'cessation'.
+// Open or unknown dates never fall after another date.
+func startsAfter(inception string, cessation string) (bool, error) {
+
+	if isOpenOrUnknown(inception) || isOpenOrUnknown(cessation) {
+		return false, nil
+	}
+
+	inception_d, err := parser.ParseString(inception)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse inception date '%s', %w", inception, err)
+	}
+
+	cessation_d, err := parser.ParseString(cessation)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse cessation date '%s', %w", cessation, err)
+	}
+
+	return inception_d.After(cessation_d)
+}
+
+// datesOverlap returns a boolean value indicating whether the EDTF date ranges 'a_inception' to 'a_cessation' and 'b_inception' to
+// 'b_cessation' (possibly) overlap. Open or unknown dates are treated as unbounded.
+func datesOverlap(a_inception string, a_cessation string, b_inception string, b_cessation string) (bool, error) {
+
+	a_starts_after, err := startsAfter(a_inception, b_cessation)
+
+	if err != nil {
+		return false, err
+	}
+
+	b_starts_after, err := startsAfter(b_inception, a_cessation)
+
+	if err != nil {
+		return false, err
+	}
+
+	return !a_starts_after && !b_starts_after, nil
+}
+
+// intersectDates returns the inception and cessation dates of the interval shared by the EDTF dates 'inception_a', 'cessation_a'
+// and 'inception_b', 'cessation_b'. The later of the two inception dates and the earlier of the two cessation dates are used. Open
+// or unknown dates defer to the other date. An error is returned if the two intervals do not overlap.
+func intersectDates(inception_a string, cessation_a string, inception_b string, cessation_b string) (string, string, error) {
+
+	inception, err := laterDate(inception_a, inception_b)
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to derive inception date, %w", err)
+	}
+
+	cessation, err := earlierDate(cessation_a, cessation_b)
+
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to derive cessation date, %w", err)
+	}
+
+	starts_after, err := startsAfter(inception, cessation)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if starts_after {
+		return "", "", fmt.Errorf("Dates %s/%s and %s/%s do not overlap", inception_a, cessation_a, inception_b, cessation_b)
+	}
+
+	return inception, cessation, nil
+}
+
+// laterDate returns whichever of the EDTF dates 'a' and 'b' has the later lower bound. If either date is open or unknown then the
+// other date is returned.
+func laterDate(a string, b string) (string, error) {
+
+	if isOpenOrUnknown(a) {
+		return b, nil
+	}
+
+	if isOpenOrUnknown(b) {
+		return a, nil
+	}
+
+	a_t, err := lowerBound(a)
+
+	if err != nil {
+		return "", err
+	}
+
+	b_t, err := lowerBound(b)
+
+	if err != nil {
+		return "", err
+	}
+
+	if b_t.After(*a_t) {
+		return b, nil
+	}
+
+	return a, nil
+}
+
+// earlierDate returns whichever of the EDTF dates 'a' and 'b' has the earlier upper bound. If either date is open or unknown
+// then the other date is returned.
+func earlierDate(a string, b string) (string, error) {
+
+	if isOpenOrUnknown(a) {
+
+		// An unknown date is more specific than an open-ended one
+
+		if edtf.IsOpen(b) && edtf.IsUnknown(a) {
+			return a, nil
+		}
+
+		return b, nil
+	}
+
+	if isOpenOrUnknown(b) {
+		return a, nil
+	}
+
+	a_t, err := upperBound(a)
+
+	if err != nil {
+		return "", err
+	}
+
+	b_t, err := upperBound(b)
+
+	if err != nil {
+		return "", err
+	}
+
+	if b_t.Before(*a_t) {
+		return b, nil
+	}
+
+	return a, nil
+}
+
+// deriveIsCurrent returns the `mz:is_current` value for a record with the EDTF dates 'inception' and 'cessation' relative to 'now'.
+// Records which have not started yet, or whose cessation date falls before 'now', are not current. Records with an open cessation
+// date are current. Records with an unknown cessation date are unknown (-1).
+func deriveIsCurrent(inception string, cessation string, now time.Time) (int64, error) {
+
+	if !isOpenOrUnknown(inception) {
+
+		t, err := lowerBound(inception)
+
+		if err != nil {
+			return -1, err
+		}
+
+		if t.After(now) {
+			return 0, nil
+		}
+	}
+
+	if edtf.IsOpen(cessation) {
+		return 1, nil
+	}
+
+	if edtf.IsUnknown(cessation) {
+		return -1, nil
+	}
+
+	t, err := upperBound(cessation)
+
+	if err != nil {
+		return -1, err
+	}
+
+	if t.Before(now) {
+		return 0, nil
+	}
+
+	return 1, nil
+}
+
+func lowerBound(edtf_str string) (*time.Time, error) {
+
+	d, err := parser.ParseString(edtf_str)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse '%s', %w", edtf_str, err)
+	}
+
+	t, err := d.Lower()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive lower bound for '%s', %w", edtf_str, err)
+	}
+
+	return t, nil
+}
+
+func upperBound(edtf_str string) (*time.Time, error) {
+
+	d, err := parser.ParseString(edtf_str)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse '%s', %w", edtf_str, err)
+	}
+
+	t, err := d.Upper()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive upper bound for '%s', %w", edtf_str, err)
+	}
+
+	return t, nil
+}
+
+func isOpenOrUnknown(edtf_str string) bool {
+	return edtf.IsOpen(edtf_str) || edtf.IsUnknown(edtf_str)
+}
@@ -0,0 +1,5 @@
+// Package edit provides methods for updating SFO Museum exhibition records, for example assigning the galleries an exhibition
+// was shown in or superseding an exhibition when its gallery is superseded. Functions operate on (and return) the raw bytes of
+// Who's On First records so that they can be used without a particular reader or writer. The `Transaction` type can be used to
+// validate and write a related set of updated records.
+package edit
@@ -0,0 +1,53 @@
+package edit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/whosonfirst/go-reader/v2"
+	wof_reader "github.com/whosonfirst/go-whosonfirst-reader/v2"
+)
+
+// LoadExhibition resolves 'code' using the exhibitions lookup 'lookup' and loads the matching exhibition record from 'exh_r'.
+// The current exhibition matching 'code' is preferred. If there is no current match then exhibitions in any other existential state
+// are considered. In both cases an error wrapping `exhibitions.MultipleCandidates` is returned if 'code' matches more than one exhibition.
+// If 'code' is not found in the lookup (for example because the lookup data is out of date) and it is a valid Who's On First ID then
+// the record is loaded directly from 'exh_r'.
+func LoadExhibition(ctx context.Context, lookup curatorial.Lookup, exh_r reader.Reader, code string) ([]byte, error) {
+
+	var exh_id int64
+
+	exh, err := exhibitions.FindCurrentExhibitionWithLookup(ctx, lookup, code)
+
+	if exhibitions.IsNotFound(err) {
+		exh, err = exhibitions.FindExhibitionWithLookup(ctx, lookup, code)
+	}
+
+	switch {
+	case err == nil:
+		exh_id = exh.WhosOnFirstId
+	case exhibitions.IsNotFound(err):
+
+		id, parse_err := strconv.ParseInt(code, 10, 64)
+
+		if parse_err != nil || id <= 0 {
+			return nil, fmt.Errorf("Failed to resolve exhibition '%s', %w", code, err)
+		}
+
+		exh_id = id
+
+	default:
+		return nil, fmt.Errorf("Failed to resolve exhibition '%s', %w", code, err)
+	}
+
+	exh_f, err := wof_reader.LoadBytes(ctx, exh_r, exh_id)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load exhibition record %d, %w", exh_id, err)
+	}
+
+	return exh_f, nil
+}
@@ -0,0 +1,121 @@
+package edit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial/exhibitions"
+	"github.com/whosonfirst/go-reader/v2"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+)
+
+func TestLoadExhibition(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*exhibitions.Exhibition{
+		&exhibitions.Exhibition{WhosOnFirstId: 100, SFOMuseumId: 1, Name: "Closed", IsCurrent: 0},
+		&exhibitions.Exhibition{WhosOnFirstId: 101, SFOMuseumId: 2, Name: "First", IsCurrent: 0},
+		&exhibitions.Exhibition{WhosOnFirstId: 102, SFOMuseumId: 2, Name: "Second", IsCurrent: 1},
+		&exhibitions.Exhibition{WhosOnFirstId: 104, SFOMuseumId: 4, Name: "Closed (first)", IsCurrent: 0},
+		&exhibitions.Exhibition{WhosOnFirstId: 105, SFOMuseumId: 4, Name: "Closed (second)", IsCurrent: 0},
+	}
+
+	lookup, err := exhibitions.NewLookupWithLookupFunc(ctx, exhibitions.NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	root := t.TempDir()
+
+	// Record 103 is not in the lookup
+
+	for _, id := range []int64{100, 101, 102, 103} {
+
+		path := recordPath(t, root, id)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+
+		if err != nil {
+			t.Fatalf("Failed to create directory for %d, %v", id, err)
+		}
+
+		err = os.WriteFile(path, newExhibition(t, id, "2019-06-01", "2020-01-05"), 0644)
+
+		if err != nil {
+			t.Fatalf("Failed to write %d, %v", id, err)
+		}
+	}
+
+	exh_r, err := reader.NewReader(ctx, "fs://"+root)
+
+	if err != nil {
+		t.Fatalf("Failed to create reader, %v", err)
+	}
+
+	tests := map[string]int64{
+		"100":                       100,
+		"sfomuseum:exhibition_id=1": 100,
+		"sfomuseum:exhibition_id=2": 102,
+		"101":                       101,
+		"103":                       103,
+	}
+
+	for code, expected := range tests {
+
+		exh_f, err := LoadExhibition(ctx, lookup, exh_r, code)
+
+		if err != nil {
+			t.Fatalf("Failed to load exhibition '%s', %v", code, err)
+		}
+
+		id, _ := properties.Id(exh_f)
+
+		if id != expected {
+			t.Fatalf("Unexpected exhibition for '%s', expected %d but got %d", code, expected, id)
+		}
+	}
+
+	for _, code := range []string{"sfomuseum:exhibition_id=3", "sfomuseum:exhibition_id=4", "106"} {
+
+		_, err := LoadExhibition(ctx, lookup, exh_r, code)
+
+		if err == nil {
+			t.Fatalf("Expected loading exhibition '%s' to fail", code)
+		}
+	}
+}
+
+func TestLoadExhibitionMultipleCandidates(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*exhibitions.Exhibition{
+		&exhibitions.Exhibition{WhosOnFirstId: 100, SFOMuseumId: 1, Name: "First", IsCurrent: 1},
+		&exhibitions.Exhibition{WhosOnFirstId: 101, SFOMuseumId: 1, Name: "Second", IsCurrent: 1},
+	}
+
+	lookup, err := exhibitions.NewLookupWithLookupFunc(ctx, exhibitions.NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	exh_r, err := reader.NewReader(ctx, "fs://"+t.TempDir())
+
+	if err != nil {
+		t.Fatalf("Failed to create reader, %v", err)
+	}
+
+	_, err = LoadExhibition(ctx, lookup, exh_r, "sfomuseum:exhibition_id=1")
+
+	var multiple exhibitions.MultipleCandidates
+
+	if !errors.As(err, &multiple) {
+		t.Fatalf("Expected MultipleCandidates error, got %v", err)
+	}
+}
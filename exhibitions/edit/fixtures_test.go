@@ -0,0 +1,96 @@
+package edit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/whosonfirst/go-whosonfirst-id"
+)
+
+// newFeature returns the GeoJSON encoding of a Feature with 'props' and 'geom'.
+func newFeature(t *testing.T, props map[string]interface{}, geom map[string]interface{}) []byte {
+
+	f := map[string]interface{}{
+		"type":       "Feature",
+		"properties": props,
+		"geometry":   geom,
+	}
+
+	body, err := json.Marshal(f)
+
+	if err != nil {
+		t.Fatalf("Failed to marshal feature, %v", err)
+	}
+
+	return body
+}
+
+// newGallery returns a gallery record with ID 'id' in the building 'building_id' whose footprint is a unit square with its
+// south-west corner at 'x', 'y'.
+func newGallery(t *testing.T, id int64, building_id int64, x float64, y float64) []byte {
+
+	props := map[string]interface{}{
+		"wof:id":                  id,
+		"wof:name":                "Gallery",
+		"wof:placetype":           "enclosure",
+		"sfomuseum:placetype":     "gallery",
+		"wof:parent_id":           building_id,
+		"wof:hierarchy":           []map[string]int64{{"building_id": building_id, "enclosure_id": id}},
+		"sfomuseum:post_security": 1,
+		"mz:is_current":           1,
+		"edtf:inception":          "2000-01-01",
+		"edtf:cessation":          "..",
+		"lbl:latitude":            y + 0.5,
+		"lbl:longitude":           x + 0.5,
+	}
+
+	geom := map[string]interface{}{
+		"type":        "Polygon",
+		"coordinates": [][][]float64{{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}, {x, y}}},
+	}
+
+	return newFeature(t, props, geom)
+}
+
+// newExhibition returns an exhibition record with ID 'id' and the EDTF dates 'inception' and 'cessation'.
+func newExhibition(t *testing.T, id int64, inception string, cessation string) []byte {
+
+	props := map[string]interface{}{
+		"wof:id":                  id,
+		"wof:name":                "Exhibition",
+		"wof:placetype":           "venue",
+		"sfomuseum:placetype":     "exhibition",
+		"wof:parent_id":           -1,
+		"wof:hierarchy":           []map[string]int64{},
+		"wof:repo":                "sfomuseum-data-exhibition",
+		"sfomuseum:exhibition_id": id,
+		"mz:is_current":           0,
+		"edtf:inception":          inception,
+		"edtf:cessation":          cessation,
+		"wof:supersedes":          []int64{},
+		"wof:superseded_by":       []int64{},
+	}
+
+	geom := map[string]interface{}{
+		"type":        "Point",
+		"coordinates": []float64{0.0, 0.0},
+	}
+
+	return newFeature(t, props, geom)
+}
+
+// newSequenceProvider returns an `id.Provider` instance returning sequential IDs starting at 'start'.
+func newSequenceProvider(t *testing.T, start int64) id.Provider {
+
+	ctx := context.Background()
+
+	pr, err := id.NewProviderWithURI(ctx, fmt.Sprintf("sequence://?start=%d", start))
+
+	if err != nil {
+		t.Fatalf("Failed to create ID provider, %v", err)
+	}
+
+	return pr
+}
@@ -0,0 +1,118 @@
+package edit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
+	"github.com/tidwall/gjson"
+)
+
+// The types of geometry that can be assigned to exhibitions spanning multiple galleries.
+const (
+	GEOMETRY_MULTIPOINT   string = "multipoint"
+	GEOMETRY_MULTIPOLYGON string = "multipolygon"
+)
+
+// galleryFootprints returns the union of the (Polygon or MultiPolygon) footprints of 'galleries' as a MultiPolygon. Walls shared
+// by adjacent galleries are dissolved and galleries whose footprints touch along an edge, or overlap, are merged in to a single
+// polygon so that the polygons in the MultiPolygon are disjoint. See `unionPolygons` for details.
+func galleryFootprints(galleries [][]byte) (orb.MultiPolygon, error) {
+
+	polys := make([]orb.Polygon, 0)
+
+	for _, gal_f := range galleries {
+
+		gal_id := gjson.GetBytes(gal_f, "properties.wof:id").Int()
+		geom_rsp := gjson.GetBytes(gal_f, "geometry")
+
+		if !geom_rsp.Exists() {
+			return nil, fmt.Errorf("Gallery %d is missing a geometry", gal_id)
+		}
+
+		geom, err := geojson.UnmarshalGeometry([]byte(geom_rsp.Raw))
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse geometry for gallery %d, %w", gal_id, err)
+		}
+
+		switch g := geom.Geometry().(type) {
+		case orb.Polygon:
+			polys = append(polys, g)
+		case orb.MultiPolygon:
+			polys = append(polys, g...)
+		default:
+			return nil, fmt.Errorf("Gallery %d does not have a footprint (its geometry is a %s)", gal_id, g.GeoJSONType())
+		}
+	}
+
+	mp, err := unionPolygons(polys)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to combine gallery footprints, %w", err)
+	}
+
+	return mp, nil
+}
+
+// orientation returns the cross product of 'a'-'b' and 'a'-'c' which is positive if 'c' is to the left of 'a'-'b', negative if
+// it is to the right and zero if the three points are collinear.
+func orientation(a orb.Point, b orb.Point, c orb.Point) float64 {
+	return (b.X()-a.X())*(c.Y()-a.Y()) - (b.Y()-a.Y())*(c.X()-a.X())
+}
+
+// labelPoint returns a label centroid for 'mp'. This is the planar centroid of 'mp' if it is contained by one of its polygons
+// otherwise the centroid of the largest polygon.
+func labelPoint(mp orb.MultiPolygon) orb.Point {
+
+	centroid, _ := planar.CentroidArea(mp)
+
+	if planar.MultiPolygonContains(mp, centroid) {
+		return centroid
+	}
+
+	var label orb.Point
+	max_area := -1.0
+
+	for _, poly := range mp {
+
+		pt, area := planar.CentroidArea(poly)
+
+		if area > max_area {
+			label = pt
+			max_area = area
+		}
+	}
+
+	return label
+}
+
+// multiPolygonUpdates returns the geometry and geometry-related property updates for an exhibition record spanning the galleries
+// whose combined footprints are 'mp'.
+func multiPolygonUpdates(mp orb.MultiPolygon) map[string]interface{} {
+
+	centroid, _ := planar.CentroidArea(mp)
+	label := labelPoint(mp)
+	bounds := mp.Bound()
+
+	bbox := []string{
+		strconv.FormatFloat(bounds.Min.X(), 'f', -1, 64),
+		strconv.FormatFloat(bounds.Min.Y(), 'f', -1, 64),
+		strconv.FormatFloat(bounds.Max.X(), 'f', -1, 64),
+		strconv.FormatFloat(bounds.Max.Y(), 'f', -1, 64),
+	}
+
+	updates := map[string]interface{}{
+		"geometry":                  geojson.NewGeometry(mp),
+		"properties.geom:bbox":      strings.Join(bbox, ","),
+		"properties.geom:latitude":  centroid.Y(),
+		"properties.geom:longitude": centroid.X(),
+		"properties.lbl:latitude":   label.Y(),
+		"properties.lbl:longitude":  label.X(),
+	}
+
+	return updates
+}
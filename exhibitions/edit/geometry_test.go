@@ -0,0 +1,209 @@
+package edit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestGalleryFootprints(t *testing.T) {
+
+	tests := map[string]struct {
+		Galleries [][]byte
+		Polygons  int
+		Area      float64
+	}{
+		"disjoint": {
+			Galleries: [][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 2.0, 0.0)},
+			Polygons:  2,
+			Area:      2.0,
+		},
+		"shared edge": {
+			Galleries: [][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 1.0, 0.0)},
+			Polygons:  1,
+			Area:      2.0,
+		},
+		"shared vertex": {
+			Galleries: [][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 1.0, 1.0)},
+			Polygons:  2,
+			Area:      2.0,
+		},
+		"overlapping": {
+			Galleries: [][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 0.5, 0.5)},
+			Polygons:  1,
+			Area:      1.75,
+		},
+		"identical": {
+			Galleries: [][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 0.0, 0.0)},
+			Polygons:  1,
+			Area:      1.0,
+		},
+	}
+
+	for label, test := range tests {
+
+		mp, err := galleryFootprints(test.Galleries)
+
+		if err != nil {
+			t.Fatalf("Failed to derive %s footprints, %v", label, err)
+		}
+
+		if len(mp) != test.Polygons {
+			t.Fatalf("Expected %d polygons for %s footprints, got %d", test.Polygons, label, len(mp))
+		}
+
+		area := planar.Area(mp)
+
+		if math.Abs(area-test.Area) > 1e-9 {
+			t.Fatalf("Expected area of %f for %s footprints, got %f", test.Area, label, area)
+		}
+	}
+}
+
+func TestGalleryFootprintsSharedEdge(t *testing.T) {
+
+	mp, err := galleryFootprints([][]byte{newGallery(t, 1, 10, 0.0, 0.0), newGallery(t, 2, 10, 1.0, 0.0)})
+
+	if err != nil {
+		t.Fatalf("Failed to derive footprints, %v", err)
+	}
+
+	if len(mp) != 1 || len(mp[0]) != 1 {
+		t.Fatalf("Expected a single polygon without holes, got %v", mp)
+	}
+
+	// The shared wall is dissolved leaving a single 2 x 1 rectangle
+
+	expected := orb.Ring{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}
+
+	if !ringsEquivalent(mp[0][0], expected) {
+		t.Fatalf("Unexpected footprint %v", mp[0][0])
+	}
+}
+
+func TestUnionPolygons(t *testing.T) {
+
+	square := func(x float64, y float64, size float64) orb.Polygon {
+		return orb.Polygon{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}}
+	}
+
+	tests := map[string]struct {
+		Polygons []orb.Polygon
+		// The number of rings in each polygon of the union
+		Rings []int
+		Area  float64
+	}{
+		"partially shared edge": {
+			Polygons: []orb.Polygon{
+				square(0, 0, 1),
+				orb.Polygon{{{1, 0.25}, {2, 0.25}, {2, 0.75}, {1, 0.75}, {1, 0.25}}},
+			},
+			Rings: []int{1},
+			Area:  1.5,
+		},
+		"contained": {
+			Polygons: []orb.Polygon{square(0, 0, 3), square(1, 1, 1)},
+			Rings:    []int{1},
+			Area:     9.0,
+		},
+		"clockwise": {
+			Polygons: []orb.Polygon{
+				square(0, 0, 1),
+				orb.Polygon{{{1, 0}, {1, 1}, {2, 1}, {2, 0}, {1, 0}}},
+			},
+			Rings: []int{1},
+			Area:  2.0,
+		},
+		"filled hole": {
+			Polygons: []orb.Polygon{
+				orb.Polygon{
+					{{0, 0}, {3, 0}, {3, 3}, {0, 3}, {0, 0}},
+					{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}},
+				},
+				square(1, 1, 1),
+			},
+			Rings: []int{1},
+			Area:  9.0,
+		},
+		"enclosed courtyard": {
+			Polygons: []orb.Polygon{
+				orb.Polygon{{{0, 0}, {3, 0}, {3, 1}, {0, 1}, {0, 0}}},
+				orb.Polygon{{{0, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 1}}},
+				orb.Polygon{{{2, 1}, {3, 1}, {3, 3}, {2, 3}, {2, 1}}},
+				orb.Polygon{{{1, 2}, {2, 2}, {2, 3}, {1, 3}, {1, 2}}},
+			},
+			Rings: []int{2},
+			Area:  8.0,
+		},
+		"crossing": {
+			Polygons: []orb.Polygon{
+				orb.Polygon{{{0, 1}, {3, 1}, {3, 2}, {0, 2}, {0, 1}}},
+				orb.Polygon{{{1, 0}, {2, 0}, {2, 3}, {1, 3}, {1, 0}}},
+			},
+			Rings: []int{1},
+			Area:  5.0,
+		},
+	}
+
+	for label, test := range tests {
+
+		mp, err := unionPolygons(test.Polygons)
+
+		if err != nil {
+			t.Fatalf("Failed to derive union of %s polygons, %v", label, err)
+		}
+
+		if len(mp) != len(test.Rings) {
+			t.Fatalf("Expected %d polygons for union of %s polygons, got %d", len(test.Rings), label, len(mp))
+		}
+
+		for idx, rings := range test.Rings {
+
+			if len(mp[idx]) != rings {
+				t.Fatalf("Expected %d rings for polygon %d of union of %s polygons, got %d", rings, idx, label, len(mp[idx]))
+			}
+		}
+
+		area := planar.Area(mp)
+
+		if math.Abs(area-test.Area) > 1e-9 {
+			t.Fatalf("Expected area of %f for union of %s polygons, got %f", test.Area, label, area)
+		}
+
+		if mp[0][0].Orientation() != orb.CCW {
+			t.Fatalf("Expected exterior ring for union of %s polygons to be counter-clockwise", label)
+		}
+	}
+}
+
+// ringsEquivalent returns a boolean value indicating whether the closed rings 'a' and 'b' contain the same points in the same order,
+// regardless of which point they start at.
+func ringsEquivalent(a orb.Ring, b orb.Ring) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	n := len(a) - 1
+
+	for offset := 0; offset < n; offset++ {
+
+		match := true
+
+		for i := 0; i < n; i++ {
+
+			if a[(i+offset)%n] != b[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
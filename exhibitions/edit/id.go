@@ -0,0 +1,116 @@
+package edit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/aaronland/go-uid"
+	"github.com/whosonfirst/go-whosonfirst-id"
+)
+
+// The scheme for `SequenceProvider` instances.
+const SEQUENCE_SCHEME string = "sequence"
+
+func init() {
+	ctx := context.Background()
+	uid.RegisterProvider(ctx, SEQUENCE_SCHEME, NewSequenceProvider)
+}
+
+// SequenceProvider implements the `aaronland/go-uid.Provider` interface returning sequential integer IDs. It does not talk
+// to any remote services and is intended for testing and for working offline. IDs are not unique across instances.
+type SequenceProvider struct {
+	uid.Provider
+	mu   *sync.Mutex
+	next int64
+}
+
+// NewSequenceProvider returns a new `SequenceProvider` instance configured by 'uri' which takes the form of:
+//
+//	sequence://?start={ID}
+//
+// Where `start` is the first ID to return. Default is 1. Use `whosonfirst/go-whosonfirst-id.NewProviderWithURI` to create a
+// `go-whosonfirst-id.Provider` instance for a `SequenceProvider` URI.
+func NewSequenceProvider(ctx context.Context, uri string) (uid.Provider, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	start := int64(1)
+
+	q := u.Query()
+
+	if q.Has("start") {
+
+		v, err := strconv.ParseInt(q.Get("start"), 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?start= parameter, %w", err)
+		}
+
+		if v < 1 {
+			return nil, fmt.Errorf("Invalid ?start= parameter, must be greater than 0")
+		}
+
+		start = v
+	}
+
+	pr := &SequenceProvider{
+		mu:   new(sync.Mutex),
+		next: start,
+	}
+
+	return pr, nil
+}
+
+// UID returns the next ID in the sequence.
+func (pr *SequenceProvider) UID(ctx context.Context, args ...interface{}) (uid.UID, error) {
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	v := pr.next
+	pr.next += 1
+
+	return uid.NewInt64UID(ctx, v)
+}
+
+// ListProvider implements the `whosonfirst/go-whosonfirst-id.Provider` interface returning each of a fixed list of IDs, in order.
+// It is intended for assigning explicit IDs to the records created by an editing operation.
+type ListProvider struct {
+	id.Provider
+	mu  *sync.Mutex
+	ids []int64
+}
+
+// NewListProvider returns a new `ListProvider` instance for 'ids'.
+func NewListProvider(ids ...int64) id.Provider {
+
+	pr := &ListProvider{
+		mu:  new(sync.Mutex),
+		ids: ids,
+	}
+
+	return pr
+}
+
+// NewID returns the next ID in the list. An error is returned if every ID has already been returned.
+func (pr *ListProvider) NewID(ctx context.Context) (int64, error) {
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if len(pr.ids) == 0 {
+		return -1, fmt.Errorf("No more IDs available")
+	}
+
+	v := pr.ids[0]
+	pr.ids = pr.ids[1:]
+
+	return v, nil
+}
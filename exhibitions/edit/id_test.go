@@ -0,0 +1,67 @@
+package edit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/whosonfirst/go-whosonfirst-id"
+)
+
+func TestSequenceProvider(t *testing.T) {
+
+	ctx := context.Background()
+
+	pr, err := id.NewProviderWithURI(ctx, "sequence://?start=1000")
+
+	if err != nil {
+		t.Fatalf("Failed to create provider, %v", err)
+	}
+
+	for _, expected := range []int64{1000, 1001, 1002} {
+
+		v, err := pr.NewID(ctx)
+
+		if err != nil {
+			t.Fatalf("Failed to create ID, %v", err)
+		}
+
+		if v != expected {
+			t.Fatalf("Expected %d, got %d", expected, v)
+		}
+	}
+
+	for _, uri := range []string{"sequence://?start=0", "sequence://?start=abc"} {
+
+		_, err := id.NewProviderWithURI(ctx, uri)
+
+		if err == nil {
+			t.Fatalf("Expected %s to fail", uri)
+		}
+	}
+}
+
+func TestListProvider(t *testing.T) {
+
+	ctx := context.Background()
+
+	pr := NewListProvider(5, 3)
+
+	for _, expected := range []int64{5, 3} {
+
+		v, err := pr.NewID(ctx)
+
+		if err != nil {
+			t.Fatalf("Failed to create ID, %v", err)
+		}
+
+		if v != expected {
+			t.Fatalf("Expected %d, got %d", expected, v)
+		}
+	}
+
+	_, err := pr.NewID(ctx)
+
+	if err == nil {
+		t.Fatalf("Expected exhausted provider to fail")
+	}
+}
@@ -0,0 +1,211 @@
+package edit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-reader/v2"
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+	"github.com/whosonfirst/go-whosonfirst-id"
+	wof_reader "github.com/whosonfirst/go-whosonfirst-reader/v2"
+)
+
+// Epochs returns the architecture records for 'parent_id' and each of its successors (derived from `wof:superseded_by`)
+// whose inception date does not fall after the EDTF date 'cessation'.
+func Epochs(ctx context.Context, arch_r reader.Reader, parent_id int64, cessation string) ([][]byte, error) {
+
+	records := make([][]byte, 0)
+	seen := make(map[int64]bool)
+
+	current_id := parent_id
+
+	for {
+
+		if seen[current_id] {
+			return nil, fmt.Errorf("Cycle detected in supersedes chain for parent record %d", current_id)
+		}
+
+		seen[current_id] = true
+
+		parent_f, err := wof_reader.LoadBytes(ctx, arch_r, current_id)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load parent record %d, %w", current_id, err)
+		}
+
+		if len(records) > 0 {
+
+			starts_after, err := startsAfter(properties.Inception(parent_f), cessation)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to compare dates for parent record %d, %w", current_id, err)
+			}
+
+			if starts_after {
+				break
+			}
+		}
+
+		records = append(records, parent_f)
+
+		superseded_by := properties.SupersededBy(parent_f)
+
+		switch len(superseded_by) {
+		case 0:
+			return records, nil
+		case 1:
+			current_id = superseded_by[0]
+		default:
+			return nil, fmt.Errorf("Parent record %d is superseded by multiple records", current_id)
+		}
+	}
+
+	return records, nil
+}
+
+// Supersede creates a new exhibition record, with an ID derived from 'id_provider', superseding the exhibition record 'exh_f'
+// and parented by the architecture record 'parent_f'. The new record's dates are the intersection of the exhibition's dates and
+// the parent's dates and its `mz:is_current` property is derived from those dates. It returns the new record followed by the
+// updated version of 'exh_f'.
+func Supersede(ctx context.Context, exh_f []byte, parent_f []byte, id_provider id.Provider) ([]byte, []byte, error) {
+
+	new_id, err := id_provider.NewID(ctx)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create new ID, %w", err)
+	}
+
+	return supersede(ctx, exh_f, parent_f, new_id, properties.Inception(exh_f), properties.Cessation(exh_f), time.Now())
+}
+
+// SupersedeEpochs supersedes the exhibition record 'exh_f' once for each of the architecture records in 'parents' (for example
+// the records returned by `Epochs`). Each new record supersedes the record created for the previous parent and its dates are
+// the intersection of the original exhibition's dates and its parent's dates. It returns the original exhibition record
+// followed by each of the new records. The final bodies of records that are superseded more than once are returned.
+func SupersedeEpochs(ctx context.Context, exh_f []byte, parents [][]byte, id_provider id.Provider) ([]*Record, error) {
+
+	exh_id, err := properties.Id(exh_f)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive exhibition ID, %w", err)
+	}
+
+	// The exhibition's original dates. Each new record is assigned the intersection of these dates and the dates of
+	// its parent record.
+
+	exh_inception := properties.Inception(exh_f)
+	exh_cessation := properties.Cessation(exh_f)
+
+	now := time.Now()
+
+	previous := &Record{
+		Id:       exh_id,
+		Original: exh_f,
+		Body:     exh_f,
+	}
+
+	records := []*Record{
+		previous,
+	}
+
+	for _, parent_f := range parents {
+
+		new_id, err := id_provider.NewID(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create new ID, %w", err)
+		}
+
+		new_f, old_f, err := supersede(ctx, previous.Body, parent_f, new_id, exh_inception, exh_cessation, now)
+
+		if err != nil {
+			return nil, err
+		}
+
+		previous.Body = old_f
+
+		// The new exhibition is the record that the next epoch (if any) supersedes
+
+		previous = &Record{
+			Id:   new_id,
+			Body: new_f,
+		}
+
+		records = append(records, previous)
+	}
+
+	return records, nil
+}
+
+// supersede creates a new exhibition record with ID 'new_id' superseding 'exh_f' and parented by 'parent_f'. The new record's dates
+// are the intersection of 'inception', 'cessation' and the parent's dates. The cessation date of 'exh_f' is replaced by the inception
+// date of the new record and, if that date falls before 'now', 'exh_f' is marked as not current. It returns the new record followed
+// by the updated version of 'exh_f'.
+func supersede(ctx context.Context, exh_f []byte, parent_f []byte, new_id int64, inception string, cessation string, now time.Time) ([]byte, []byte, error) {
+
+	exh_id, err := properties.Id(exh_f)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive exhibition ID, %w", err)
+	}
+
+	parent_id := gjson.GetBytes(parent_f, "properties.wof:id").Int()
+
+	new_inception, new_cessation, err := intersectDates(inception, cessation, properties.Inception(parent_f), properties.Cessation(parent_f))
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive dates for parent record %d, %w", parent_id, err)
+	}
+
+	new_is_current, err := deriveIsCurrent(new_inception, new_cessation, now)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive is current for parent record %d, %w", parent_id, err)
+	}
+
+	new_updates := map[string]interface{}{
+		"properties.id":             new_id,
+		"properties.wof:id":         new_id,
+		"properties.wof:parent_id":  parent_id,
+		"properties.wof:hierarchy":  gjson.GetBytes(parent_f, "properties.wof:hierarchy").Value(),
+		"properties.mz:is_current":  new_is_current,
+		"properties.edtf:inception": new_inception,
+		"properties.edtf:cessation": new_cessation,
+		"properties.wof:supersedes": []int64{exh_id},
+	}
+
+	_, new_exh_f, err := export.AssignPropertiesIfChanged(ctx, exh_f, new_updates)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to assign properties for new exhibition, %w", err)
+	}
+
+	old_updates := map[string]interface{}{
+		"properties.wof:superseded_by": []int64{new_id},
+		"properties.edtf:cessation":    new_inception,
+	}
+
+	// The previous record is only marked as not current if its (updated) dates have ended. Records superseded
+	// by a record which has not started yet remain current until it does.
+
+	old_is_current, err := deriveIsCurrent(properties.Inception(exh_f), new_inception, now)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive is current for exhibition %d, %w", exh_id, err)
+	}
+
+	if old_is_current == 0 {
+		old_updates["properties.mz:is_current"] = 0
+	}
+
+	_, old_exh_f, err := export.AssignPropertiesIfChanged(ctx, exh_f, old_updates)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to assign properties for exhibition %d, %w", exh_id, err)
+	}
+
+	return new_exh_f, old_exh_f, nil
+}
@@ -0,0 +1,162 @@
+package edit
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+)
+
+func TestSupersede(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+	parent_f := newGallery(t, 1, 10, 0.0, 0.0)
+
+	id_provider := newSequenceProvider(t, 200)
+
+	new_f, old_f, err := Supersede(ctx, exh_f, parent_f, id_provider)
+
+	if err != nil {
+		t.Fatalf("Failed to supersede exhibition, %v", err)
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:id").Int() != 200 {
+		t.Fatalf("Unexpected ID for new record: %s", gjson.GetBytes(new_f, "properties.wof:id").Raw)
+	}
+
+	if gjson.GetBytes(new_f, "properties.wof:parent_id").Int() != 1 {
+		t.Fatalf("Unexpected parent ID for new record: %s", gjson.GetBytes(new_f, "properties.wof:parent_id").Raw)
+	}
+
+	if properties.Inception(new_f) != "2019-06-01" || properties.Cessation(new_f) != "2020-01-05" {
+		t.Fatalf("Unexpected dates for new record: %s - %s", properties.Inception(new_f), properties.Cessation(new_f))
+	}
+
+	// The exhibition closed in 2020 so the new record is not current, even though its parent is
+
+	if gjson.GetBytes(new_f, "properties.mz:is_current").Int() != 0 {
+		t.Fatalf("Unexpected is current for new record: %s", gjson.GetBytes(new_f, "properties.mz:is_current").Raw)
+	}
+
+	if !slices.Equal(properties.Supersedes(new_f), []int64{100}) {
+		t.Fatalf("Unexpected supersedes for new record: %v", properties.Supersedes(new_f))
+	}
+
+	if !slices.Equal(properties.SupersededBy(old_f), []int64{200}) {
+		t.Fatalf("Unexpected superseded by for previous record: %v", properties.SupersededBy(old_f))
+	}
+
+	if properties.Cessation(old_f) != "2019-06-01" {
+		t.Fatalf("Unexpected cessation for previous record: %s", properties.Cessation(old_f))
+	}
+
+	// An ongoing exhibition in an ongoing gallery is current
+
+	ongoing_f := newExhibition(t, 101, "2019-06-01", "..")
+
+	new_f, _, err = Supersede(ctx, ongoing_f, parent_f, id_provider)
+
+	if err != nil {
+		t.Fatalf("Failed to supersede ongoing exhibition, %v", err)
+	}
+
+	if gjson.GetBytes(new_f, "properties.mz:is_current").Int() != 1 {
+		t.Fatalf("Unexpected is current for ongoing record: %s", gjson.GetBytes(new_f, "properties.mz:is_current").Raw)
+	}
+
+	// A parent whose dates do not overlap the exhibition's dates
+
+	closed_f, err := export.AssignProperties(ctx, newGallery(t, 2, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.edtf:cessation": "2010-01-01",
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create closed gallery, %v", err)
+	}
+
+	_, _, err = Supersede(ctx, exh_f, closed_f, id_provider)
+
+	if err == nil {
+		t.Fatalf("Expected superseding with a parent whose dates do not overlap to fail")
+	}
+}
+
+func TestSupersedeEpochs(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+
+	first_f, err := export.AssignProperties(ctx, newGallery(t, 1, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.edtf:cessation":    "2019-09-01",
+		"properties.wof:superseded_by": []int64{2},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create first gallery, %v", err)
+	}
+
+	second_f, err := export.AssignProperties(ctx, newGallery(t, 2, 10, 0.0, 0.0), map[string]interface{}{
+		"properties.edtf:inception": "2019-09-01",
+		"properties.wof:supersedes": []int64{1},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create second gallery, %v", err)
+	}
+
+	id_provider := newSequenceProvider(t, 200)
+
+	records, err := SupersedeEpochs(ctx, exh_f, [][]byte{first_f, second_f}, id_provider)
+
+	if err != nil {
+		t.Fatalf("Failed to supersede exhibition, %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	tests := []struct {
+		Id           int64
+		Created      bool
+		Inception    string
+		Cessation    string
+		Supersedes   []int64
+		SupersededBy []int64
+	}{
+		{100, false, "2019-06-01", "2019-06-01", []int64{}, []int64{200}},
+		{200, true, "2019-06-01", "2019-09-01", []int64{100}, []int64{201}},
+		{201, true, "2019-09-01", "2020-01-05", []int64{200}, []int64{}},
+	}
+
+	for idx, expected := range tests {
+
+		r := records[idx]
+
+		if r.Id != expected.Id {
+			t.Fatalf("Unexpected ID for record %d: %d", idx, r.Id)
+		}
+
+		if (r.Original == nil) != expected.Created {
+			t.Fatalf("Unexpected original bytes for record %d", r.Id)
+		}
+
+		if properties.Inception(r.Body) != expected.Inception || properties.Cessation(r.Body) != expected.Cessation {
+			t.Fatalf("Unexpected dates for record %d: %s - %s", r.Id, properties.Inception(r.Body), properties.Cessation(r.Body))
+		}
+
+		if !slices.Equal(properties.Supersedes(r.Body), expected.Supersedes) {
+			t.Fatalf("Unexpected supersedes for record %d: %v", r.Id, properties.Supersedes(r.Body))
+		}
+
+		if !slices.Equal(properties.SupersededBy(r.Body), expected.SupersededBy) {
+			t.Fatalf("Unexpected superseded by for record %d: %v", r.Id, properties.SupersededBy(r.Body))
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package edit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	sfom_writer "github.com/sfomuseum/go-sfomuseum-writer/v3"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+	"github.com/whosonfirst/go-whosonfirst-uri"
+	"github.com/whosonfirst/go-writer/v3"
+)
+
+// Record is an exhibition record created or modified by an editing operation.
+type Record struct {
+	// The Who's On First ID of the record.
+	Id int64
+	// The original (unmodified) bytes of the record. This is nil if the record is being created.
+	Original []byte
+	// The (exported) bytes to write.
+	Body []byte
+}
+
+// Transaction stages a set of related records (for example a new exhibition record and the record it supersedes) so that
+// they can be validated before any of them are written and, if a write fails, rolled back.
+type Transaction struct {
+	writer   writer.Writer
+	exporter export.Exporter
+	records  []*Record
+}
+
+// NewTransaction returns a new `Transaction` instance whose records will be exported using 'ex' and written to 'wr'.
+func NewTransaction(wr writer.Writer, ex export.Exporter) *Transaction {
+
+	tx := &Transaction{
+		writer:   wr,
+		exporter: ex,
+		records:  make([]*Record, 0),
+	}
+
+	return tx
+}
+
+// Stage exports 'body' and adds it to the transaction. 'original' is the current version of the record or nil if the record
+// is being created. If the record has already been staged its body is replaced (but its original bytes are retained).
+func (tx *Transaction) Stage(ctx context.Context, original []byte, body []byte) error {
+
+	_, exported, err := tx.exporter.Export(ctx, body)
+
+	if err != nil {
+		return fmt.Errorf("Failed to export record, %w", err)
+	}
+
+	id, err := properties.Id(exported)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive ID for record, %w", err)
+	}
+
+	for _, r := range tx.records {
+
+		if r.Id == id {
+			r.Body = exported
+			return nil
+		}
+	}
+
+	r := &Record{
+		Id:       id,
+		Original: original,
+		Body:     exported,
+	}
+
+	tx.records = append(tx.records, r)
+	return nil
+}
+
+// Records returns the list of staged records.
+func (tx *Transaction) Records() []*Record {
+	return tx.records
+}
+
+// Validate ensures that the `wof:supersedes` and `wof:superseded_by` properties of every staged record are consistent with the
+// other staged records that they reference.
+func (tx *Transaction) Validate() error {
+
+	staged := make(map[int64]*Record)
+
+	for _, r := range tx.records {
+		staged[r.Id] = r
+	}
+
+	for _, r := range tx.records {
+
+		for _, other_id := range properties.Supersedes(r.Body) {
+
+			other, ok := staged[other_id]
+
+			if ok && !slices.Contains(properties.SupersededBy(other.Body), r.Id) {
+				return fmt.Errorf("Record %d supersedes %d but %d is not superseded by %d", r.Id, other_id, other_id, r.Id)
+			}
+		}
+
+		for _, other_id := range properties.SupersededBy(r.Body) {
+
+			other, ok := staged[other_id]
+
+			if ok && !slices.Contains(properties.Supersedes(other.Body), r.Id) {
+				return fmt.Errorf("Record %d is superseded by %d but %d does not supersede %d", r.Id, other_id, other_id, r.Id)
+			}
+		}
+
+		if gjson.GetBytes(r.Body, "properties.wof:id").Int() != r.Id {
+			return fmt.Errorf("Record %d has an inconsistent wof:id property", r.Id)
+		}
+	}
+
+	return nil
+}
+
+// Commit writes all the staged records. New records are written before existing records are updated so that existing
+// records never point to records that have not been written. If a write fails then every record written so far, and the
+// record that failed to be written, is rolled back: existing records are restored to their original bytes and new records
+// are removed (if the writer targets the local filesystem). The error returned describes the state that the records have
+// been left in.
+func (tx *Transaction) Commit(ctx context.Context) error {
+
+	ordered := make([]*Record, 0, len(tx.records))
+
+	for _, r := range tx.records {
+
+		if r.Original == nil {
+			ordered = append(ordered, r)
+		}
+	}
+
+	for _, r := range tx.records {
+
+		if r.Original != nil {
+			ordered = append(ordered, r)
+		}
+	}
+
+	written := make([]*Record, 0)
+
+	for _, r := range ordered {
+
+		_, err := sfom_writer.WriteBytes(ctx, tx.writer, r.Body)
+
+		if err != nil {
+
+			// The writer may have left the failed record partially written so it is rolled back along with
+			// every record written before it.
+
+			write_err := fmt.Errorf("Failed to write record %d, %w", r.Id, err)
+			return tx.rollback(ctx, append(written, r), write_err)
+		}
+
+		written = append(written, r)
+	}
+
+	return nil
+}
+
+// rollback restores (or removes) 'written' and returns an error describing 'write_err' and the outcome of the rollback. 'written'
+// may include records that were only partially written, or not written at all.
+func (tx *Transaction) rollback(ctx context.Context, written []*Record, write_err error) error {
+
+	restored := make([]string, 0)
+	failed := make([]string, 0)
+
+	for _, r := range slices.Backward(written) {
+
+		rel_path, err := uri.Id2RelPath(r.Id)
+
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%d (failed to derive path, %v)", r.Id, err))
+			continue
+		}
+
+		if r.Original != nil {
+
+			_, err := tx.writer.Write(ctx, rel_path, bytes.NewReader(r.Original))
+
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%d (failed to restore original record, %v)", r.Id, err))
+				continue
+			}
+
+			restored = append(restored, fmt.Sprintf("%d (restored)", r.Id))
+			continue
+		}
+
+		abs_path := tx.writer.WriterURI(ctx, rel_path)
+
+		if !filepath.IsAbs(abs_path) {
+			failed = append(failed, fmt.Sprintf("%d (new record at %s must be removed manually)", r.Id, abs_path))
+			continue
+		}
+
+		err = os.Remove(abs_path)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			restored = append(restored, fmt.Sprintf("%d (not written)", r.Id))
+			continue
+		}
+
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%d (failed to remove new record at %s, %v)", r.Id, abs_path, err))
+			continue
+		}
+
+		restored = append(restored, fmt.Sprintf("%d (removed)", r.Id))
+	}
+
+	if len(failed) == 0 {
+		return fmt.Errorf("%w. All changes were rolled back: %s", write_err, strings.Join(restored, ", "))
+	}
+
+	return fmt.Errorf("%w. Rollback was incomplete, rolled back: [%s], NOT rolled back: [%s]", write_err, strings.Join(restored, ", "), strings.Join(failed, ", "))
+}
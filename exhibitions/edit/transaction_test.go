@@ -0,0 +1,348 @@
+package edit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/whosonfirst/go-whosonfirst-export/v3"
+	"github.com/whosonfirst/go-whosonfirst-uri"
+	"github.com/whosonfirst/go-writer/v3"
+)
+
+// failingWriter wraps a `writer.Writer` instance and fails the first time it is asked to write the record 'fail_id'.
+type failingWriter struct {
+	writer.Writer
+	fail_id int64
+	failed  bool
+}
+
+func (wr *failingWriter) Write(ctx context.Context, path string, r io.ReadSeeker) (int64, error) {
+
+	id, _, err := uri.ParseURI(path)
+
+	if err == nil && id == wr.fail_id && !wr.failed {
+		wr.failed = true
+		return 0, fmt.Errorf("Failed to write %d", id)
+	}
+
+	return wr.Writer.Write(ctx, path, r)
+}
+
+// partialWriter wraps a `writer.Writer` instance and, the first time it is asked to write the record 'fail_id', writes only part
+// of the record before failing.
+type partialWriter struct {
+	writer.Writer
+	fail_id int64
+	failed  bool
+}
+
+func (wr *partialWriter) Write(ctx context.Context, path string, r io.ReadSeeker) (int64, error) {
+
+	id, _, err := uri.ParseURI(path)
+
+	if err != nil || id != wr.fail_id || wr.failed {
+		return wr.Writer.Write(ctx, path, r)
+	}
+
+	wr.failed = true
+
+	body, err := io.ReadAll(r)
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := wr.Writer.Write(ctx, path, bytes.NewReader(body[:len(body)/2]))
+
+	if err != nil {
+		return n, err
+	}
+
+	return n, fmt.Errorf("Failed to write %d after %d bytes", id, n)
+}
+
+func TestTransaction(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+	parent_f := newGallery(t, 1, 10, 0.0, 0.0)
+
+	records, err := SupersedeEpochs(ctx, exh_f, [][]byte{parent_f}, newSequenceProvider(t, 200))
+
+	if err != nil {
+		t.Fatalf("Failed to supersede exhibition, %v", err)
+	}
+
+	ex, err := export.NewExporter(ctx, "sfomuseum://")
+
+	if err != nil {
+		t.Fatalf("Failed to create exporter, %v", err)
+	}
+
+	root := t.TempDir()
+
+	wr, err := writer.NewWriter(ctx, "fs://"+root)
+
+	if err != nil {
+		t.Fatalf("Failed to create writer, %v", err)
+	}
+
+	// Write the original exhibition record so that it can be restored
+
+	tx := NewTransaction(wr, ex)
+
+	err = tx.Stage(ctx, nil, exh_f)
+
+	if err != nil {
+		t.Fatalf("Failed to stage original record, %v", err)
+	}
+
+	err = tx.Commit(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to commit original record, %v", err)
+	}
+
+	exh_path := recordPath(t, root, 100)
+	new_path := recordPath(t, root, 200)
+
+	written, err := os.ReadFile(exh_path)
+
+	if err != nil {
+		t.Fatalf("Failed to read original record, %v", err)
+	}
+
+	// A failed commit rolls back the new record
+
+	failing_wr := &failingWriter{Writer: wr, fail_id: 100}
+
+	tx = NewTransaction(failing_wr, ex)
+
+	for _, r := range records {
+
+		original := r.Original
+
+		if original != nil {
+			original = written
+		}
+
+		err := tx.Stage(ctx, original, r.Body)
+
+		if err != nil {
+			t.Fatalf("Failed to stage record %d, %v", r.Id, err)
+		}
+	}
+
+	err = tx.Validate()
+
+	if err != nil {
+		t.Fatalf("Failed to validate records, %v", err)
+	}
+
+	err = tx.Commit(ctx)
+
+	if err == nil || !strings.Contains(err.Error(), "All changes were rolled back") {
+		t.Fatalf("Expected commit to fail and be rolled back, %v", err)
+	}
+
+	_, err = os.Stat(new_path)
+
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected new record to be removed, %v", err)
+	}
+
+	restored, err := os.ReadFile(exh_path)
+
+	if err != nil {
+		t.Fatalf("Failed to read original record, %v", err)
+	}
+
+	if !bytes.Equal(restored, written) {
+		t.Fatalf("Expected original record to be unchanged")
+	}
+
+	// A successful commit
+
+	tx = NewTransaction(wr, ex)
+
+	for _, r := range records {
+
+		err := tx.Stage(ctx, r.Original, r.Body)
+
+		if err != nil {
+			t.Fatalf("Failed to stage record %d, %v", r.Id, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to commit records, %v", err)
+	}
+
+	for _, path := range []string{exh_path, new_path} {
+
+		_, err := os.Stat(path)
+
+		if err != nil {
+			t.Fatalf("Expected %s to exist, %v", path, err)
+		}
+	}
+}
+
+func TestTransactionPartialWrite(t *testing.T) {
+
+	ctx := context.Background()
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+	parent_f := newGallery(t, 1, 10, 0.0, 0.0)
+
+	records, err := SupersedeEpochs(ctx, exh_f, [][]byte{parent_f}, newSequenceProvider(t, 200))
+
+	if err != nil {
+		t.Fatalf("Failed to supersede exhibition, %v", err)
+	}
+
+	ex, err := export.NewExporter(ctx, "sfomuseum://")
+
+	if err != nil {
+		t.Fatalf("Failed to create exporter, %v", err)
+	}
+
+	// Fail writing the existing record (which is written last) and the new record (which is written first)
+
+	for _, fail_id := range []int64{100, 200} {
+
+		root := t.TempDir()
+
+		wr, err := writer.NewWriter(ctx, "fs://"+root)
+
+		if err != nil {
+			t.Fatalf("Failed to create writer, %v", err)
+		}
+
+		tx := NewTransaction(wr, ex)
+
+		err = tx.Stage(ctx, nil, exh_f)
+
+		if err != nil {
+			t.Fatalf("Failed to stage original record, %v", err)
+		}
+
+		err = tx.Commit(ctx)
+
+		if err != nil {
+			t.Fatalf("Failed to commit original record, %v", err)
+		}
+
+		exh_path := recordPath(t, root, 100)
+		new_path := recordPath(t, root, 200)
+
+		written, err := os.ReadFile(exh_path)
+
+		if err != nil {
+			t.Fatalf("Failed to read original record, %v", err)
+		}
+
+		tx = NewTransaction(&partialWriter{Writer: wr, fail_id: fail_id}, ex)
+
+		for _, r := range records {
+
+			original := r.Original
+
+			if original != nil {
+				original = written
+			}
+
+			err := tx.Stage(ctx, original, r.Body)
+
+			if err != nil {
+				t.Fatalf("Failed to stage record %d, %v", r.Id, err)
+			}
+		}
+
+		err = tx.Commit(ctx)
+
+		if err == nil || !strings.Contains(err.Error(), "All changes were rolled back") {
+			t.Fatalf("Expected commit failing on %d to be rolled back, %v", fail_id, err)
+		}
+
+		_, err = os.Stat(new_path)
+
+		if !os.IsNotExist(err) {
+			t.Fatalf("Expected new record to be removed after commit failing on %d, %v", fail_id, err)
+		}
+
+		restored, err := os.ReadFile(exh_path)
+
+		if err != nil {
+			t.Fatalf("Failed to read original record, %v", err)
+		}
+
+		if !bytes.Equal(restored, written) {
+			t.Fatalf("Expected original record to be restored after commit failing on %d", fail_id)
+		}
+	}
+}
+
+func TestTransactionValidate(t *testing.T) {
+
+	ctx := context.Background()
+
+	ex, err := export.NewExporter(ctx, "sfomuseum://")
+
+	if err != nil {
+		t.Fatalf("Failed to create exporter, %v", err)
+	}
+
+	wr, err := writer.NewWriter(ctx, "null://")
+
+	if err != nil {
+		t.Fatalf("Failed to create writer, %v", err)
+	}
+
+	exh_f := newExhibition(t, 100, "2019-06-01", "2020-01-05")
+
+	new_f, err := export.AssignProperties(ctx, newExhibition(t, 200, "2019-06-01", "2020-01-05"), map[string]interface{}{
+		"properties.wof:supersedes": []int64{100},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create new record, %v", err)
+	}
+
+	tx := NewTransaction(wr, ex)
+
+	for _, body := range [][]byte{exh_f, new_f} {
+
+		err := tx.Stage(ctx, nil, body)
+
+		if err != nil {
+			t.Fatalf("Failed to stage record, %v", err)
+		}
+	}
+
+	err = tx.Validate()
+
+	if err == nil {
+		t.Fatalf("Expected non-reciprocal supersedes to fail validation")
+	}
+}
+
+func recordPath(t *testing.T, root string, id int64) string {
+
+	rel_path, err := uri.Id2RelPath(id)
+
+	if err != nil {
+		t.Fatalf("Failed to derive path for %d, %v", id, err)
+	}
+
+	return filepath.Join(root, rel_path)
+}
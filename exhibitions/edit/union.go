@@ -0,0 +1,517 @@
+package edit
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// union_precision is the number of grid cells per coordinate unit that points are snapped to when dissolving polygons. Snapping
+// ensures that the shared vertices and walls of adjacent polygons compare as equal. For coordinates in decimal degrees this is
+// roughly 0.1 millimetres.
+const union_precision float64 = 1e9
+
+// union_tolerance is the distance within which a point is considered to be on a line segment when dissolving polygons.
+const union_tolerance float64 = 1 / union_precision
+
+// edge is a directed line segment of a polygon ring oriented so that the interior of the polygon it belongs to is on its left.
+type edge struct {
+	a orb.Point
+	b orb.Point
+	// The index of the polygon the edge belongs to.
+	poly int
+}
+
+// unionPolygons returns the union of 'polys' as a MultiPolygon whose polygons are disjoint (they may touch at a single point). Edges
+// shared by adjacent polygons are dissolved and polygons that touch along an edge, or overlap, are merged in to a single polygon.
+// Coordinates are snapped to a grid of `union_precision` cells per unit.
+func unionPolygons(polys []orb.Polygon) (orb.MultiPolygon, error) {
+
+	oriented := make([]orb.Polygon, 0, len(polys))
+
+	for _, poly := range polys {
+
+		p := orientPolygon(poly)
+
+		if p != nil {
+			oriented = append(oriented, p)
+		}
+	}
+
+	if len(oriented) == 0 {
+		return nil, fmt.Errorf("No valid polygons to combine")
+	}
+
+	edges := make([]edge, 0)
+
+	for idx, poly := range oriented {
+
+		for _, ring := range poly {
+
+			for i := 1; i < len(ring); i++ {
+
+				a := snapPoint(ring[i-1])
+				b := snapPoint(ring[i])
+
+				if a == b {
+					continue
+				}
+
+				edges = append(edges, edge{a: a, b: b, poly: idx})
+			}
+		}
+	}
+
+	boundary := dissolveEdges(splitEdges(edges), oriented)
+
+	rings, err := linkEdges(boundary)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return assembleRings(rings)
+}
+
+// orientPolygon returns a closed copy of 'poly' whose exterior ring is counter-clockwise and whose interior rings are clockwise,
+// or nil if its exterior ring is degenerate. Degenerate interior rings are removed.
+func orientPolygon(poly orb.Polygon) orb.Polygon {
+
+	oriented := make(orb.Polygon, 0, len(poly))
+
+	for idx, r := range poly {
+
+		ring := r.Clone()
+
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0])
+		}
+
+		if len(ring) < 4 || ring.Orientation() == 0 {
+
+			if idx == 0 {
+				return nil
+			}
+
+			continue
+		}
+
+		if (idx == 0 && ring.Orientation() == orb.CW) || (idx > 0 && ring.Orientation() == orb.CCW) {
+			ring.Reverse()
+		}
+
+		oriented = append(oriented, ring)
+	}
+
+	return oriented
+}
+
+// snapPoint returns 'pt' snapped to a grid of `union_precision` cells per unit.
+func snapPoint(pt orb.Point) orb.Point {
+	return orb.Point{
+		math.Round(pt.X()*union_precision) / union_precision,
+		math.Round(pt.Y()*union_precision) / union_precision,
+	}
+}
+
+// splitEdges splits each of 'edges' at every point where it is crossed or touched by another edge so that the resulting
+// edges only meet at their end points and edges that overlap are split in to identical (or reversed) segments.
+func splitEdges(edges []edge) []edge {
+
+	splits := make([][]orb.Point, len(edges))
+
+	for i := 0; i < len(edges); i++ {
+
+		ei := edges[i]
+		bi := orb.Bound{Min: ei.a, Max: ei.a}.Extend(ei.b).Pad(union_tolerance)
+
+		for j := i + 1; j < len(edges); j++ {
+
+			ej := edges[j]
+
+			if !bi.Intersects(orb.Bound{Min: ej.a, Max: ej.a}.Extend(ej.b)) {
+				continue
+			}
+
+			touches := false
+
+			for _, pt := range []orb.Point{ej.a, ej.b} {
+
+				if pointOnSegment(pt, ei.a, ei.b) {
+					splits[i] = append(splits[i], pt)
+					touches = true
+				}
+			}
+
+			for _, pt := range []orb.Point{ei.a, ei.b} {
+
+				if pointOnSegment(pt, ej.a, ej.b) {
+					splits[j] = append(splits[j], pt)
+					touches = true
+				}
+			}
+
+			if touches {
+				continue
+			}
+
+			pt, ok := crossingPoint(ei.a, ei.b, ej.a, ej.b)
+
+			if ok {
+				pt = snapPoint(pt)
+				splits[i] = append(splits[i], pt)
+				splits[j] = append(splits[j], pt)
+			}
+		}
+	}
+
+	split := make([]edge, 0, len(edges))
+
+	for idx, e := range edges {
+
+		if len(splits[idx]) == 0 {
+			split = append(split, e)
+			continue
+		}
+
+		pts := append([]orb.Point{e.a, e.b}, splits[idx]...)
+
+		dx := e.b.X() - e.a.X()
+		dy := e.b.Y() - e.a.Y()
+
+		slices.SortFunc(pts, func(p orb.Point, q orb.Point) int {
+			tp := (p.X()-e.a.X())*dx + (p.Y()-e.a.Y())*dy
+			tq := (q.X()-e.a.X())*dx + (q.Y()-e.a.Y())*dy
+			return cmp.Compare(tp, tq)
+		})
+
+		pts = slices.Compact(pts)
+
+		for i := 1; i < len(pts); i++ {
+			split = append(split, edge{a: pts[i-1], b: pts[i], poly: e.poly})
+		}
+	}
+
+	return split
+}
+
+// dissolveEdges returns the subset of 'edges' (derived from 'polys' by `splitEdges`) that form the boundary of the union of 'polys'.
+// Edges that fall inside another polygon are removed, edges shared by two polygons whose interiors are on opposite sides of the
+// edge (for example the shared wall of adjacent galleries) are removed and duplicate edges are only included once.
+func dissolveEdges(edges []edge, polys []orb.Polygon) []edge {
+
+	type segment struct {
+		a orb.Point
+		b orb.Point
+	}
+
+	groups := make(map[segment][]edge)
+	order := make([]segment, 0)
+
+	for _, e := range edges {
+
+		key := segment{e.a, e.b}
+
+		if cmpPoint(e.b, e.a) < 0 {
+			key = segment{e.b, e.a}
+		}
+
+		_, exists := groups[key]
+
+		if !exists {
+			order = append(order, key)
+		}
+
+		groups[key] = append(groups[key], e)
+	}
+
+	boundary := make([]edge, 0)
+
+	for _, key := range order {
+
+		group := groups[key]
+		first := group[0]
+
+		opposed := slices.ContainsFunc(group, func(e edge) bool {
+			return e.a != first.a
+		})
+
+		if opposed {
+			continue
+		}
+
+		mid := orb.Point{(first.a.X() + first.b.X()) / 2, (first.a.Y() + first.b.Y()) / 2}
+		inside := false
+
+		for idx, poly := range polys {
+
+			in_group := slices.ContainsFunc(group, func(e edge) bool {
+				return e.poly == idx
+			})
+
+			if in_group || pointOnPolygonBoundary(mid, poly) {
+				continue
+			}
+
+			if planar.PolygonContains(poly, mid) {
+				inside = true
+				break
+			}
+		}
+
+		if !inside {
+			boundary = append(boundary, first)
+		}
+	}
+
+	return boundary
+}
+
+// linkEdges links the directed boundary edges 'edges' in to closed rings. Where more than one edge leaves a point the edge
+// making the sharpest left turn is followed, so polygons which only touch at a point are traced as separate rings.
+func linkEdges(edges []edge) ([]orb.Ring, error) {
+
+	outgoing := make(map[orb.Point][]int)
+
+	for idx, e := range edges {
+		outgoing[e.a] = append(outgoing[e.a], idx)
+	}
+
+	used := make([]bool, len(edges))
+	rings := make([]orb.Ring, 0)
+
+	for start := range edges {
+
+		if used[start] {
+			continue
+		}
+
+		ring := orb.Ring{edges[start].a}
+		current := start
+
+		for steps := 0; ; steps++ {
+
+			if steps > len(edges) {
+				return nil, fmt.Errorf("Failed to link footprint edges in to a ring")
+			}
+
+			used[current] = true
+
+			e := edges[current]
+			ring = append(ring, e.b)
+
+			next := -1
+			max_turn := math.Inf(-1)
+
+			for _, candidate := range outgoing[e.b] {
+
+				if used[candidate] && candidate != start {
+					continue
+				}
+
+				turn := turnAngle(e, edges[candidate])
+
+				if turn > max_turn {
+					next = candidate
+					max_turn = turn
+				}
+			}
+
+			if next == -1 {
+				return nil, fmt.Errorf("Failed to link footprint edges in to a ring, no edge leaves %v", e.b)
+			}
+
+			if next == start {
+				break
+			}
+
+			current = next
+		}
+
+		rings = append(rings, ring)
+	}
+
+	return rings, nil
+}
+
+// assembleRings returns a MultiPolygon derived from the closed rings 'rings'. Counter-clockwise rings are exterior rings and
+// clockwise rings are interior rings (holes) which are assigned to the smallest exterior ring containing them.
+func assembleRings(rings []orb.Ring) (orb.MultiPolygon, error) {
+
+	shells := make([]orb.Ring, 0)
+	holes := make([]orb.Ring, 0)
+
+	for _, r := range rings {
+
+		ring := removeCollinearPoints(r)
+
+		if len(ring) < 4 {
+			continue
+		}
+
+		switch ring.Orientation() {
+		case orb.CCW:
+			shells = append(shells, ring)
+		case orb.CW:
+			holes = append(holes, ring)
+		}
+	}
+
+	mp := make(orb.MultiPolygon, len(shells))
+
+	for idx, shell := range shells {
+		mp[idx] = orb.Polygon{shell}
+	}
+
+	for _, hole := range holes {
+
+		owner := -1
+		owner_area := math.Inf(1)
+
+		for idx, shell := range shells {
+
+			contains := !slices.ContainsFunc(hole, func(pt orb.Point) bool {
+				return !planar.RingContains(shell, pt)
+			})
+
+			if !contains {
+				continue
+			}
+
+			area := planar.Area(shell)
+
+			if area < owner_area {
+				owner = idx
+				owner_area = area
+			}
+		}
+
+		if owner == -1 {
+			return nil, fmt.Errorf("Failed to find the exterior ring for an interior ring of the combined footprints")
+		}
+
+		mp[owner] = append(mp[owner], hole)
+	}
+
+	return mp, nil
+}
+
+// removeCollinearPoints returns a copy of the closed ring 'r' without any points that fall on the line between their neighbours.
+func removeCollinearPoints(r orb.Ring) orb.Ring {
+
+	pts := slices.Clone(r[:len(r)-1])
+
+	for changed := true; changed && len(pts) > 3; {
+
+		changed = false
+
+		for i := 0; i < len(pts) && len(pts) > 3; i++ {
+
+			prev := pts[(i+len(pts)-1)%len(pts)]
+			next := pts[(i+1)%len(pts)]
+
+			if pointOnSegment(pts[i], prev, next) {
+				pts = slices.Delete(pts, i, i+1)
+				changed = true
+				i -= 1
+			}
+		}
+	}
+
+	return append(orb.Ring(pts), pts[0])
+}
+
+// turnAngle returns the angle, in radians, between the direction of 'in' and the direction of 'out'. Left turns are positive
+// and right turns are negative.
+func turnAngle(in edge, out edge) float64 {
+
+	ix := in.b.X() - in.a.X()
+	iy := in.b.Y() - in.a.Y()
+	ox := out.b.X() - out.a.X()
+	oy := out.b.Y() - out.a.Y()
+
+	return math.Atan2(ix*oy-iy*ox, ix*ox+iy*oy)
+}
+
+// crossingPoint returns the point where the segments 'p1'-'p2' and 'q1'-'q2' cross and a boolean value indicating whether they
+// cross at a single point in the interior of both segments.
+func crossingPoint(p1 orb.Point, p2 orb.Point, q1 orb.Point, q2 orb.Point) (orb.Point, bool) {
+
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if !(((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))) {
+		return orb.Point{}, false
+	}
+
+	t := d1 / (d1 - d2)
+
+	pt := orb.Point{
+		p1.X() + t*(p2.X()-p1.X()),
+		p1.Y() + t*(p2.Y()-p1.Y()),
+	}
+
+	return pt, true
+}
+
+// pointOnSegment returns a boolean value indicating whether 'pt' is within `union_tolerance` of the segment 'a'-'b' but is not
+// one of its end points.
+func pointOnSegment(pt orb.Point, a orb.Point, b orb.Point) bool {
+
+	if pt == a || pt == b {
+		return false
+	}
+
+	return segmentDistance(pt, a, b) < union_tolerance
+}
+
+// pointOnPolygonBoundary returns a boolean value indicating whether 'pt' is within `union_tolerance` of any of the rings of 'poly'.
+func pointOnPolygonBoundary(pt orb.Point, poly orb.Polygon) bool {
+
+	for _, ring := range poly {
+
+		for i := 1; i < len(ring); i++ {
+
+			if segmentDistance(pt, ring[i-1], ring[i]) < union_tolerance {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// segmentDistance returns the (planar) distance between 'pt' and the segment 'a'-'b'.
+func segmentDistance(pt orb.Point, a orb.Point, b orb.Point) float64 {
+
+	dx := b.X() - a.X()
+	dy := b.Y() - a.Y()
+
+	t := 0.0
+	length := dx*dx + dy*dy
+
+	if length > 0 {
+		t = ((pt.X()-a.X())*dx + (pt.Y()-a.Y())*dy) / length
+		t = max(0, min(1, t))
+	}
+
+	return math.Hypot(pt.X()-(a.X()+t*dx), pt.Y()-(a.Y()+t*dy))
+}
+
+// cmpPoint compares 'p' and 'q' by their X and then their Y coordinates.
+func cmpPoint(p orb.Point, q orb.Point) int {
+
+	c := cmp.Compare(p.X(), q.X())
+
+	if c != 0 {
+		return c
+	}
+
+	return cmp.Compare(p.Y(), q.Y())
+}
@@ -0,0 +1,142 @@
+package edit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+)
+
+// ValidateGallery ensures that 'gal_f' is a gallery record that is not deprecated or superseded and that is either current
+// or whose dates overlap the dates of the exhibition record 'exh_f'.
+func ValidateGallery(gal_f []byte, exh_f []byte) error {
+
+	gal_id := gjson.GetBytes(gal_f, "properties.wof:id").Int()
+
+	if !isGallery(gal_f) {
+
+		pt, _ := properties.Placetype(gal_f)
+		sfom_pt := gjson.GetBytes(gal_f, "properties.sfomuseum:placetype").String()
+
+		return fmt.Errorf("Record %d is not a gallery (wof:placetype is '%s', sfomuseum:placetype is '%s')", gal_id, pt, sfom_pt)
+	}
+
+	existential_flags, err := curatorial.DeriveExistentialFlags(gal_f)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive existential flags for gallery %d, %w", gal_id, err)
+	}
+
+	if existential_flags.IsDeprecated == 1 {
+		return fmt.Errorf("Gallery %d has been deprecated (%s)", gal_id, properties.Deprecated(gal_f))
+	}
+
+	superseded_by := properties.SupersededBy(gal_f)
+
+	if len(superseded_by) > 0 {
+		return fmt.Errorf("Gallery %d has been superseded by %s, assign the current gallery instead (or use the -follow-superseded flag to follow the wof:superseded_by chain)", gal_id, joinIds(superseded_by))
+	}
+
+	if existential_flags.IsSuperseded == 1 {
+		return fmt.Errorf("Gallery %d has been superseded", gal_id)
+	}
+
+	if existential_flags.IsCurrent == 1 {
+		return nil
+	}
+
+	gal_inception := properties.Inception(gal_f)
+	gal_cessation := properties.Cessation(gal_f)
+
+	exh_inception := properties.Inception(exh_f)
+	exh_cessation := properties.Cessation(exh_f)
+
+	overlaps, err := datesOverlap(gal_inception, gal_cessation, exh_inception, exh_cessation)
+
+	if err != nil {
+		return fmt.Errorf("Failed to compare dates for gallery %d, %w", gal_id, err)
+	}
+
+	if !overlaps {
+		return fmt.Errorf("Gallery %d is not current and its dates (%s - %s) do not overlap the exhibition's dates (%s - %s)", gal_id, gal_inception, gal_cessation, exh_inception, exh_cessation)
+	}
+
+	return nil
+}
+
+// ValidateGalleryBuildings ensures that all of 'galleries' share at least one building (derived from the "building_id" key
+// in their hierarchies).
+func ValidateGalleryBuildings(galleries [][]byte) error {
+
+	if len(galleries) < 2 {
+		return nil
+	}
+
+	var shared []int64
+
+	for idx, gal_f := range galleries {
+
+		gal_id := gjson.GetBytes(gal_f, "properties.wof:id").Int()
+		building_ids := make([]int64, 0)
+
+		for _, h := range properties.Hierarchies(gal_f) {
+
+			building_id, ok := h["building_id"]
+
+			if ok && building_id > 0 && !slices.Contains(building_ids, building_id) {
+				building_ids = append(building_ids, building_id)
+			}
+		}
+
+		if len(building_ids) == 0 {
+			return fmt.Errorf("Gallery %d does not have a building_id in its hierarchy", gal_id)
+		}
+
+		if idx == 0 {
+			shared = building_ids
+			continue
+		}
+
+		shared = slices.DeleteFunc(shared, func(id int64) bool {
+			return !slices.Contains(building_ids, id)
+		})
+
+		if len(shared) == 0 {
+			return fmt.Errorf("Gallery %d does not share a building with the other galleries (its buildings are %v)", gal_id, building_ids)
+		}
+	}
+
+	return nil
+}
+
+// isGallery returns a boolean value indicating whether 'body' is a gallery record.
+func isGallery(body []byte) bool {
+
+	if gjson.GetBytes(body, "properties.sfomuseum:placetype").String() == "gallery" {
+		return true
+	}
+
+	pt, err := properties.Placetype(body)
+
+	if err != nil {
+		return false
+	}
+
+	return pt == "gallery"
+}
+
+// joinIds returns 'ids' as a comma-separated string.
+func joinIds(ids []int64) string {
+
+	str_ids := make([]string, len(ids))
+
+	for i, id := range ids {
+		str_ids[i] = strconv.FormatInt(id, 10)
+	}
+
+	return strings.Join(str_ids, ", ")
+}
@@ -1,6 +1,7 @@
 package exhibitions
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -43,3 +44,85 @@ func IsMultipleCandidates(e error) bool {
 		return false
 	}
 }
+
+// IncompletePrecompiledData is an error returned when the precompiled (embedded) exhibitions data was compiled before the gallery,
+// date, post-security and lineage properties were added to `Exhibition` records and can not be used to answer a query about them.
+type IncompletePrecompiledData struct{ filename string }
+
+func (e IncompletePrecompiledData) Error() string {
+	return fmt.Sprintf("The precompiled exhibitions data in '%s' does not contain gallery, date, post-security or lineage properties. Regenerate it using the compile-exhibitions-data tool or use a lookup derived from another source", e.filename)
+}
+
+func (e IncompletePrecompiledData) String() string {
+	return e.Error()
+}
+
+type LineageCycle struct{ id int64 }
+
+func (e LineageCycle) Error() string {
+	return fmt.Sprintf("Cycle detected in the lineage of exhibition %d", e.id)
+}
+
+func (e LineageCycle) String() string {
+	return e.Error()
+}
+
+type BrokenLineage struct {
+	id      int64
+	pointer int64
+}
+
+func (e BrokenLineage) Error() string {
+	return fmt.Sprintf("Exhibition %d has a broken lineage pointer to exhibition %d", e.id, e.pointer)
+}
+
+func (e BrokenLineage) String() string {
+	return e.Error()
+}
+
+type BranchingLineage struct{ id int64 }
+
+func (e BranchingLineage) Error() string {
+	return fmt.Sprintf("Exhibition %d has multiple lineage pointers in the same direction", e.id)
+}
+
+func (e BranchingLineage) String() string {
+	return e.Error()
+}
+
+func IsLineageCycle(e error) bool {
+
+	switch e.(type) {
+	case LineageCycle, *LineageCycle:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsBrokenLineage(e error) bool {
+
+	switch e.(type) {
+	case BrokenLineage, *BrokenLineage:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsBranchingLineage(e error) bool {
+
+	switch e.(type) {
+	case BranchingLineage, *BranchingLineage:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsIncompletePrecompiledData returns a boolean value indicating whether 'e' is, or wraps, a `IncompletePrecompiledData` error.
+func IsIncompletePrecompiledData(e error) bool {
+
+	var v IncompletePrecompiledData
+	return errors.As(e, &v)
+}
@@ -30,3 +30,32 @@ func TestMultipleCandidates(t *testing.T) {
 		t.Fatalf("Invalid stringification")
 	}
 }
+
+func TestLineageErrors(t *testing.T) {
+
+	cycle := LineageCycle{1}
+
+	if !IsLineageCycle(cycle) {
+		t.Fatalf("Expected LineageCycle error")
+	}
+
+	if cycle.String() != "Cycle detected in the lineage of exhibition 1" {
+		t.Fatalf("Invalid stringification")
+	}
+
+	broken := BrokenLineage{1, 2}
+
+	if !IsBrokenLineage(broken) {
+		t.Fatalf("Expected BrokenLineage error")
+	}
+
+	if broken.String() != "Exhibition 1 has a broken lineage pointer to exhibition 2" {
+		t.Fatalf("Invalid stringification")
+	}
+
+	branching := BranchingLineage{1}
+
+	if !IsBranchingLineage(branching) {
+		t.Fatalf("Expected BranchingLineage error")
+	}
+}
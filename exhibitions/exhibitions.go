@@ -2,26 +2,95 @@ package exhibitions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/sfomuseum/go-sfomuseum-curatorial"
 )
 
+// The values used to indicate whether an exhibition is located post-security.
+const (
+	POST_SECURITY_UNKNOWN int64 = -1
+	POST_SECURITY_FALSE   int64 = 0
+	POST_SECURITY_TRUE    int64 = 1
+	// The exhibition spans multiple galleries some of which are located post-security and some of which are not.
+	POST_SECURITY_MIXED int64 = 2
+)
+
 type Exhibition struct {
 	WhosOnFirstId  int64  `json:"wof:id"`
 	Name           string `json:"wof:name"`
 	SFOMuseumId    int64  `json:"sfomuseum:exhibition_id"`
 	SFOMuseumWWWId int64  `json:"sfomuseum_www:exhibition_id"`
 	IsCurrent      int64  `json:"mz:is_current"`
+	IsDeprecated   int64  `json:"mz:is_deprecated,omitempty"`
+	IsCeased       int64  `json:"mz:is_ceased,omitempty"`
+	IsSuperseded   int64  `json:"mz:is_superseded,omitempty"`
+	// The EDTF string for when the exhibition opened.
+	Inception string `json:"edtf:inception,omitempty"`
+	// The EDTF string for when the exhibition closed.
+	Cessation string `json:"edtf:cessation,omitempty"`
+	// The earliest possible inception date, derived from `Inception` and formatted using `DATE_LAYOUT`. Empty if the inception date is open or unknown.
+	InceptionLower string `json:"date:inception_lower,omitempty"`
+	// The latest possible inception date, derived from `Inception` and formatted using `DATE_LAYOUT`. Empty if the inception date is open or unknown.
+	InceptionUpper string `json:"date:inception_upper,omitempty"`
+	// The earliest possible cessation date, derived from `Cessation` and formatted using `DATE_LAYOUT`. Empty if the cessation date is open or unknown.
+	CessationLower string `json:"date:cessation_lower,omitempty"`
+	// The latest possible cessation date, derived from `Cessation` and formatted using `DATE_LAYOUT`. Empty if the cessation date is open or unknown.
+	CessationUpper string `json:"date:cessation_upper,omitempty"`
+	// The Who's On First ID of the exhibition's parent (gallery) record. -4 indicates that the exhibition spans multiple galleries.
+	ParentId int64 `json:"wof:parent_id,omitempty"`
+	// The exhibition's Who's On First hierarchies.
+	Hierarchy []map[string]int64 `json:"wof:hierarchy,omitempty"`
+	// Whether the exhibition is located post-security. One of the `POST_SECURITY_` constants. Records whose encoded data does
+	// not contain this property (for example data compiled before it was introduced) are decoded as `POST_SECURITY_UNKNOWN`.
+	PostSecurity int64 `json:"sfomuseum:post_security"`
+	// The Who's On First IDs of the exhibition records that this record supersedes.
+	Supersedes []int64 `json:"wof:supersedes,omitempty"`
+	// The Who's On First IDs of the exhibition records that this record has been superseded by.
+	SupersededBy []int64 `json:"wof:superseded_by,omitempty"`
+	// The Who's On First IDs of the galleries that the exhibition was shown in, derived from its hierarchies.
+	GalleryIds []int64 `json:"sfomuseum:gallery_id,omitempty"`
+}
+
+// UnmarshalJSON decodes 'b' in to the Exhibition, ensuring that a missing `sfomuseum:post_security` property is decoded as
+// `POST_SECURITY_UNKNOWN` rather than `POST_SECURITY_FALSE`.
+func (w *Exhibition) UnmarshalJSON(b []byte) error {
+
+	// exhibition has the same fields as Exhibition but not its methods, avoiding infinite recursion
+	type exhibition Exhibition
+
+	v := exhibition{
+		PostSecurity: POST_SECURITY_UNKNOWN,
+	}
+
+	err := json.Unmarshal(b, &v)
+
+	if err != nil {
+		return err
+	}
 
-	// To do: is current stuff
-	// To do (maybe): galleries
+	*w = Exhibition(v)
+	return nil
 }
 
 func (w *Exhibition) String() string {
 	return fmt.Sprintf("%d %s FM: %d WWW: %d", w.WhosOnFirstId, w.Name, w.SFOMuseumId, w.SFOMuseumWWWId)
 }
 
+// ExistentialFlags returns the existential properties of the Exhibition.
+func (w *Exhibition) ExistentialFlags() *curatorial.ExistentialFlags {
+
+	f := &curatorial.ExistentialFlags{
+		IsCurrent:    w.IsCurrent,
+		IsDeprecated: w.IsDeprecated,
+		IsCeased:     w.IsCeased,
+		IsSuperseded: w.IsSuperseded,
+	}
+
+	return f
+}
+
 // Return the current Exhibition matching 'code'. Multiple matches throw an error.
 func FindCurrentExhibition(ctx context.Context, code string) (*Exhibition, error) {
 
@@ -36,18 +105,24 @@ func FindCurrentExhibition(ctx context.Context, code string) (*Exhibition, error
 
 // Return the current Exhibition matching 'code' with a custom curatorial.Lookup instance. Multiple matches throw an error.
 func FindCurrentExhibitionWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) (*Exhibition, error) {
+	return FindExhibitionWithLookup(ctx, lookup, code, curatorial.StateCurrent)
+}
+
+// Return the Exhibition matching 'code' that is in any of the existential states defined by 'states' with a custom curatorial.Lookup
+// instance. If 'states' is empty then all the Exhibition instances matching 'code' are considered. Multiple matches throw an error.
+func FindExhibitionWithLookup(ctx context.Context, lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) (*Exhibition, error) {
 
-	current, err := FindExhibitionsCurrentWithLookup(ctx, lookup, code)
+	matches, err := FindExhibitions(ctx, lookup, code, states...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	switch len(current) {
+	switch len(matches) {
 	case 0:
 		return nil, NotFound{code}
 	case 1:
-		return current[0], nil
+		return matches[0], nil
 	default:
 		return nil, MultipleCandidates{code}
 	}
@@ -68,26 +143,53 @@ func FindExhibitionsCurrent(ctx context.Context, code string) ([]*Exhibition, er
 
 // Returns all Exhibition instances matching 'code' that are marked as current with a custom curatorial.Lookup instance.
 func FindExhibitionsCurrentWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) ([]*Exhibition, error) {
+	return FindExhibitions(ctx, lookup, code, curatorial.StateCurrent)
+}
+
+// Returns all Exhibition instances matching 'code' that are in any of the existential states defined by 'states' with a custom
+// curatorial.Lookup instance. If 'states' is empty then all the Exhibition instances matching 'code' are returned.
+func FindExhibitions(ctx context.Context, lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) ([]*Exhibition, error) {
+
+	typed_lookup := curatorial.AsTypedLookup[*Exhibition](lookup)
 
-	rsp, err := lookup.Find(ctx, code)
+	rsp, err := typed_lookup.Find(ctx, code)
 
 	if err != nil {
+
+		if curatorial.IsUnexpectedType(err) {
+			return nil, err
+		}
+
 		return nil, NotFound{code}
 	}
 
-	current := make([]*Exhibition, 0)
-
-	for _, r := range rsp {
+	matches := make([]*Exhibition, 0)
 
-		g := r.(*Exhibition)
+	for _, g := range rsp {
 
-		// if g.IsCurrent == 0 {
-		if g.IsCurrent != 1 {
+		if !curatorial.MatchesExistentialStates(g, states...) {
 			continue
 		}
 
-		current = append(current, g)
+		matches = append(matches, g)
+	}
+
+	return matches, nil
+}
+
+// SearchExhibitions returns all Exhibition instances whose name matches 'query', ranked by score.
+func SearchExhibitions(ctx context.Context, query string) ([]*curatorial.SearchResult[*Exhibition], error) {
+
+	lookup, err := NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
 	}
 
-	return current, nil
+	return SearchExhibitionsWithLookup(ctx, lookup, query)
+}
+
+// SearchExhibitionsWithLookup returns all Exhibition instances whose name matches 'query', ranked by score, with a custom curatorial.Lookup instance.
+func SearchExhibitionsWithLookup(ctx context.Context, lookup curatorial.Lookup, query string) ([]*curatorial.SearchResult[*Exhibition], error) {
+	return curatorial.SearchTypedLookup[*Exhibition](ctx, lookup, query)
 }
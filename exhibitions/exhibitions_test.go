@@ -2,7 +2,10 @@ package exhibitions
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
 )
 
 func TestFindCurrentExhibitions(t *testing.T) {
@@ -26,3 +29,124 @@ func TestFindCurrentExhibitions(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchExhibitions(t *testing.T) {
+
+	tests := map[string]int64{
+		"parasols from Gifu": 1159159409,
+	}
+
+	ctx := context.Background()
+
+	for q, id := range tests {
+
+		results, err := SearchExhibitions(ctx, q)
+
+		if err != nil {
+			t.Fatalf("Failed to search exhibitions for '%s', %v", q, err)
+		}
+
+		if len(results) == 0 {
+			t.Fatalf("No results searching exhibitions for '%s'", q)
+		}
+
+		if results[0].Record.WhosOnFirstId != id {
+			t.Fatalf("Unexpected first result for '%s'. Got %d but expected %d", q, results[0].Record.WhosOnFirstId, id)
+		}
+	}
+}
+
+func TestFindExhibitions(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 999999, Name: "Current", IsCurrent: 1},
+		&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 999999, Name: "Unknown", IsCurrent: -1},
+		&Exhibition{WhosOnFirstId: 3, SFOMuseumId: 999999, Name: "Superseded", IsCurrent: 0, IsSuperseded: 1},
+	}
+
+	lookup_func := NewLookupFuncWithExhibitions(ctx, exhibitions_list)
+	lookup, err := NewLookupWithLookupFunc(ctx, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	tests := map[int][]curatorial.ExistentialState{
+		3: []curatorial.ExistentialState{},
+		1: []curatorial.ExistentialState{curatorial.StateCurrent},
+		2: []curatorial.ExistentialState{curatorial.StateCurrent, curatorial.StateUnknown},
+	}
+
+	for expected, states := range tests {
+
+		rsp, err := FindExhibitions(ctx, lookup, "999999", states...)
+
+		if err != nil {
+			t.Fatalf("Failed to find exhibitions for %v, %v", states, err)
+		}
+
+		if len(rsp) != expected {
+			t.Fatalf("Expected %d results for %v, got %d", expected, states, len(rsp))
+		}
+	}
+
+	rsp, err := FindExhibitions(ctx, lookup, "999999", curatorial.StateSuperseded)
+
+	if err != nil {
+		t.Fatalf("Failed to find superseded exhibitions, %v", err)
+	}
+
+	if len(rsp) != 1 || rsp[0].WhosOnFirstId != 3 {
+		t.Fatalf("Unexpected results for superseded exhibitions")
+	}
+}
+
+func TestExhibitionPostSecurity(t *testing.T) {
+
+	tests := map[string]int64{
+		`{"wof:id": 1, "wof:name": "Missing"}`:                             POST_SECURITY_UNKNOWN,
+		`{"wof:id": 2, "wof:name": "False", "sfomuseum:post_security": 0}`: POST_SECURITY_FALSE,
+		`{"wof:id": 3, "wof:name": "True", "sfomuseum:post_security": 1}`:  POST_SECURITY_TRUE,
+		`{"wof:id": 4, "wof:name": "Mixed", "sfomuseum:post_security": 2}`: POST_SECURITY_MIXED,
+	}
+
+	for body, expected := range tests {
+
+		var exh Exhibition
+
+		err := json.Unmarshal([]byte(body), &exh)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal %s, %v", body, err)
+		}
+
+		if exh.PostSecurity != expected {
+			t.Fatalf("Unexpected post security for %s, expected %d but got %d", body, expected, exh.PostSecurity)
+		}
+	}
+
+	// Embedded data predates the sfomuseum:post_security property
+
+	ctx := context.Background()
+
+	lookup, err := NewLookup(ctx, "exhibitions://")
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	exhibitions_list, err := FindExhibitions(ctx, lookup, "1159159407")
+
+	if err != nil {
+		t.Fatalf("Failed to find embedded exhibition, %v", err)
+	}
+
+	for _, exh := range exhibitions_list {
+
+		if exh.PostSecurity != POST_SECURITY_UNKNOWN {
+			t.Fatalf("Expected embedded exhibition to have unknown post security, got %d", exh.PostSecurity)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package exhibitions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+)
+
+// galleryCode returns the code used to index exhibitions by the Who's On First ID of a gallery they were shown in.
+func galleryCode(gallery_id int64) string {
+	return fmt.Sprintf("sfomuseum:gallery_id=%d", gallery_id)
+}
+
+// Returns all the Exhibition instances shown in the gallery whose Who's On First ID is 'gallery_id'. This includes exhibitions that
+// span multiple galleries (whose `wof:parent_id` is -4). If 'states' is not empty only exhibitions in those existential states are returned.
+func ExhibitionsInGallery(ctx context.Context, gallery_id int64, states ...curatorial.ExistentialState) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return ExhibitionsInGalleryWithLookup(ctx, lookup, gallery_id, states...)
+}
+
+// Returns all the Exhibition instances shown in the gallery whose Who's On First ID is 'gallery_id' with a custom curatorial.Lookup instance.
+// This includes exhibitions that span multiple galleries (whose `wof:parent_id` is -4). If 'states' is not empty only exhibitions in those
+// existential states are returned.
+func ExhibitionsInGalleryWithLookup(ctx context.Context, lookup curatorial.Lookup, gallery_id int64, states ...curatorial.ExistentialState) ([]*Exhibition, error) {
+	return FindExhibitions(ctx, lookup, galleryCode(gallery_id), states...)
+}
+
+// Returns the Who's On First IDs of the galleries that the Exhibition matching 'code' was shown in. Multiple matches throw an error.
+func GalleriesForExhibition(ctx context.Context, code string) ([]int64, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return GalleriesForExhibitionWithLookup(ctx, lookup, code)
+}
+
+// Returns the Who's On First IDs of the galleries that the Exhibition matching 'code' was shown in with a custom curatorial.Lookup instance.
+// Multiple matches throw an error.
+func GalleriesForExhibitionWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) ([]int64, error) {
+
+	rsp, err := FindExhibitions(ctx, lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(rsp) {
+	case 0:
+		return nil, NotFound{code}
+	case 1:
+		return rsp[0].GalleryIds, nil
+	default:
+		return nil, MultipleCandidates{code}
+	}
+}
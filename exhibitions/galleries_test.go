@@ -0,0 +1,96 @@
+package exhibitions
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+)
+
+func TestGalleryIndexes(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{
+			WhosOnFirstId: 101,
+			SFOMuseumId:   1,
+			Name:          "Single gallery",
+			IsCurrent:     0,
+			ParentId:      1001,
+			GalleryIds:    []int64{1001},
+		},
+		&Exhibition{
+			WhosOnFirstId: 102,
+			SFOMuseumId:   2,
+			Name:          "Multiple galleries",
+			IsCurrent:     1,
+			ParentId:      -4,
+			GalleryIds:    []int64{1001, 1002},
+		},
+		&Exhibition{
+			WhosOnFirstId: 103,
+			SFOMuseumId:   3,
+			Name:          "No gallery",
+			IsCurrent:     1,
+			ParentId:      -1,
+		},
+	}
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	in_gallery, err := ExhibitionsInGalleryWithLookup(ctx, lookup, 1001)
+
+	if err != nil {
+		t.Fatalf("Failed to find exhibitions in gallery, %v", err)
+	}
+
+	ids := exhibitionIds(in_gallery)
+
+	if !slices.Equal(ids, []int64{101, 102}) {
+		t.Fatalf("Unexpected exhibitions in gallery 1001: %v", ids)
+	}
+
+	current, err := ExhibitionsInGalleryWithLookup(ctx, lookup, 1001, curatorial.StateCurrent)
+
+	if err != nil {
+		t.Fatalf("Failed to find current exhibitions in gallery, %v", err)
+	}
+
+	ids = exhibitionIds(current)
+
+	if !slices.Equal(ids, []int64{102}) {
+		t.Fatalf("Unexpected current exhibitions in gallery 1001: %v", ids)
+	}
+
+	_, err = ExhibitionsInGalleryWithLookup(ctx, lookup, 1003)
+
+	if !IsNotFound(err) {
+		t.Fatalf("Expected gallery 1003 to have no exhibitions, %v", err)
+	}
+
+	galleries, err := GalleriesForExhibitionWithLookup(ctx, lookup, "102")
+
+	if err != nil {
+		t.Fatalf("Failed to find galleries for exhibition, %v", err)
+	}
+
+	if !slices.Equal(galleries, []int64{1001, 1002}) {
+		t.Fatalf("Unexpected galleries for exhibition 102: %v", galleries)
+	}
+
+	galleries, err = GalleriesForExhibitionWithLookup(ctx, lookup, "103")
+
+	if err != nil {
+		t.Fatalf("Failed to find galleries for exhibition, %v", err)
+	}
+
+	if len(galleries) != 0 {
+		t.Fatalf("Unexpected galleries for exhibition 103: %v", galleries)
+	}
+}
@@ -0,0 +1,202 @@
+package exhibitions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
+)
+
+// Returns the full lineage, ordered from the original record to the canonical record, of the exhibition chain that the Exhibition
+// matching 'code' belongs to. Exhibition chains are defined by the `wof:supersedes` and `wof:superseded_by` properties.
+func Lineage(ctx context.Context, code string) ([]*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return LineageWithLookup(ctx, lookup, code)
+}
+
+// Returns the full lineage, ordered from the original record to the canonical record, of the exhibition chain that the Exhibition
+// matching 'code' belongs to with a custom curatorial.Lookup instance. If 'code' matches multiple records (for example an SFO Museum
+// exhibition ID shared by every record in a chain) they must all belong to the same chain otherwise a `MultipleCandidates` error is returned.
+func LineageWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) ([]*Exhibition, error) {
+
+	candidates, err := FindExhibitions(ctx, lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, NotFound{code}
+	}
+
+	lineage, err := walkLineage(ctx, lookup, candidates[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range candidates[1:] {
+
+		in_lineage := slices.ContainsFunc(lineage, func(l *Exhibition) bool {
+			return l.WhosOnFirstId == e.WhosOnFirstId
+		})
+
+		if !in_lineage {
+			return nil, MultipleCandidates{code}
+		}
+	}
+
+	return lineage, nil
+}
+
+// Returns the canonical record (the record that has not been superseded) in the exhibition chain that the Exhibition matching 'code' belongs to.
+func CanonicalExhibition(ctx context.Context, code string) (*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return CanonicalExhibitionWithLookup(ctx, lookup, code)
+}
+
+// Returns the canonical record (the record that has not been superseded) in the exhibition chain that the Exhibition matching 'code'
+// belongs to with a custom curatorial.Lookup instance.
+func CanonicalExhibitionWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) (*Exhibition, error) {
+
+	lineage, err := LineageWithLookup(ctx, lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lineage[len(lineage)-1], nil
+}
+
+// Returns the original record (the record that does not supersede any other record) in the exhibition chain that the Exhibition matching 'code' belongs to.
+func OriginalExhibition(ctx context.Context, code string) (*Exhibition, error) {
+
+	lookup, err := newCompleteDefaultLookup(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return OriginalExhibitionWithLookup(ctx, lookup, code)
+}
+
+// Returns the original record (the record that does not supersede any other record) in the exhibition chain that the Exhibition matching
+// 'code' belongs to with a custom curatorial.Lookup instance.
+func OriginalExhibitionWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) (*Exhibition, error) {
+
+	lineage, err := LineageWithLookup(ctx, lookup, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lineage[0], nil
+}
+
+// walkLineage follows the `wof:supersedes` and `wof:superseded_by` pointers of 'start' in both directions and returns
+// the resulting chain ordered from the original record to the canonical record.
+func walkLineage(ctx context.Context, lookup curatorial.Lookup, start *Exhibition) ([]*Exhibition, error) {
+
+	seen := map[int64]bool{
+		start.WhosOnFirstId: true,
+	}
+
+	older, err := followLineage(ctx, lookup, start, seen, lineageOlder)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newer, err := followLineage(ctx, lookup, start, seen, lineageNewer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(older)
+
+	lineage := make([]*Exhibition, 0, len(older)+len(newer)+1)
+	lineage = append(lineage, older...)
+	lineage = append(lineage, start)
+	lineage = append(lineage, newer...)
+
+	return lineage, nil
+}
+
+// lineageDirection returns the pointers to follow (from a record) and the pointers that are expected to point back (from the next record).
+type lineageDirection func(*Exhibition) ([]int64, []int64)
+
+func lineageOlder(e *Exhibition) ([]int64, []int64) {
+	return e.Supersedes, e.SupersededBy
+}
+
+func lineageNewer(e *Exhibition) ([]int64, []int64) {
+	return e.SupersededBy, e.Supersedes
+}
+
+// followLineage follows the pointers defined by 'direction' from 'start' until a record with no further pointers is found.
+func followLineage(ctx context.Context, lookup curatorial.Lookup, start *Exhibition, seen map[int64]bool, direction lineageDirection) ([]*Exhibition, error) {
+
+	chain := make([]*Exhibition, 0)
+	current := start
+
+	for {
+
+		pointers, _ := direction(current)
+
+		switch len(pointers) {
+		case 0:
+			return chain, nil
+		case 1:
+			// pass
+		default:
+			return nil, BranchingLineage{current.WhosOnFirstId}
+		}
+
+		next_id := pointers[0]
+
+		if seen[next_id] {
+			return nil, LineageCycle{next_id}
+		}
+
+		rsp, err := FindExhibitions(ctx, lookup, fmt.Sprintf("wof:id=%d", next_id))
+
+		if err != nil {
+
+			if IsNotFound(err) {
+				return nil, BrokenLineage{current.WhosOnFirstId, next_id}
+			}
+
+			return nil, err
+		}
+
+		if len(rsp) != 1 {
+			return nil, BrokenLineage{current.WhosOnFirstId, next_id}
+		}
+
+		next := rsp[0]
+
+		_, back_pointers := direction(next)
+
+		if !slices.Contains(back_pointers, current.WhosOnFirstId) {
+			return nil, BrokenLineage{current.WhosOnFirstId, next_id}
+		}
+
+		seen[next_id] = true
+		chain = append(chain, next)
+		current = next
+	}
+}
@@ -0,0 +1,124 @@
+package exhibitions
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestLineage(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1845, Name: "Original", SupersededBy: []int64{2}},
+		&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 1845, Name: "Moved", Supersedes: []int64{1}, SupersededBy: []int64{3}},
+		&Exhibition{WhosOnFirstId: 3, SFOMuseumId: 1845, Name: "Canonical", IsCurrent: 1, Supersedes: []int64{2}},
+		&Exhibition{WhosOnFirstId: 4, SFOMuseumId: 1846, Name: "Standalone", IsCurrent: 1},
+	}
+
+	lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, exhibitions_list))
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	for _, code := range []string{"1", "2", "3", "1845"} {
+
+		lineage, err := LineageWithLookup(ctx, lookup, code)
+
+		if err != nil {
+			t.Fatalf("Failed to derive lineage for %s, %v", code, err)
+		}
+
+		ids := exhibitionIds(lineage)
+
+		if !slices.Equal(ids, []int64{1, 2, 3}) {
+			t.Fatalf("Unexpected lineage for %s: %v", code, ids)
+		}
+	}
+
+	canonical, err := CanonicalExhibitionWithLookup(ctx, lookup, "1")
+
+	if err != nil {
+		t.Fatalf("Failed to derive canonical exhibition, %v", err)
+	}
+
+	if canonical.WhosOnFirstId != 3 {
+		t.Fatalf("Unexpected canonical exhibition: %d", canonical.WhosOnFirstId)
+	}
+
+	original, err := OriginalExhibitionWithLookup(ctx, lookup, "3")
+
+	if err != nil {
+		t.Fatalf("Failed to derive original exhibition, %v", err)
+	}
+
+	if original.WhosOnFirstId != 1 {
+		t.Fatalf("Unexpected original exhibition: %d", original.WhosOnFirstId)
+	}
+
+	lineage, err := LineageWithLookup(ctx, lookup, "4")
+
+	if err != nil {
+		t.Fatalf("Failed to derive lineage for standalone exhibition, %v", err)
+	}
+
+	if !slices.Equal(exhibitionIds(lineage), []int64{4}) {
+		t.Fatalf("Unexpected lineage for standalone exhibition")
+	}
+}
+
+func TestBrokenLineages(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		Exhibitions []*Exhibition
+		Check       func(error) bool
+	}{
+		"cycle": {
+			Exhibitions: []*Exhibition{
+				&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1, Supersedes: []int64{2}, SupersededBy: []int64{2}},
+				&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 2, Supersedes: []int64{1}, SupersededBy: []int64{1}},
+			},
+			Check: IsLineageCycle,
+		},
+		"missing": {
+			Exhibitions: []*Exhibition{
+				&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1, SupersededBy: []int64{99}},
+			},
+			Check: IsBrokenLineage,
+		},
+		"not reciprocal": {
+			Exhibitions: []*Exhibition{
+				&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1, SupersededBy: []int64{2}},
+				&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 2},
+			},
+			Check: IsBrokenLineage,
+		},
+		"branching": {
+			Exhibitions: []*Exhibition{
+				&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 1, SupersededBy: []int64{2, 3}},
+				&Exhibition{WhosOnFirstId: 2, SFOMuseumId: 2, Supersedes: []int64{1}},
+				&Exhibition{WhosOnFirstId: 3, SFOMuseumId: 3, Supersedes: []int64{1}},
+			},
+			Check: IsBranchingLineage,
+		},
+	}
+
+	for label, test := range tests {
+
+		lookup, err := NewLookupWithLookupFunc(ctx, NewLookupFuncWithExhibitions(ctx, test.Exhibitions))
+
+		if err != nil {
+			t.Fatalf("Failed to create lookup for %s, %v", label, err)
+		}
+
+		_, err = LineageWithLookup(ctx, lookup, "wof:id=1")
+
+		if !test.Check(err) {
+			t.Fatalf("Unexpected error for %s lineage, %v", label, err)
+		}
+	}
+}
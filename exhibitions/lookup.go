@@ -5,207 +5,197 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
 	"sync"
-	"sync/atomic"
 
 	"github.com/sfomuseum/go-sfomuseum-curatorial"
-	"github.com/sfomuseum/go-sfomuseum-curatorial/data"
 )
 
-var lookup_table *sync.Map
-var lookup_idx int64
-
-var lookup_init sync.Once
-var lookup_init_err error
-
-type ExhibitionsLookupFunc func(context.Context)
-
-type ExhibitionsLookup struct {
-	curatorial.Lookup
+// ExhibitionsLookupFunc is a function that populates a `ExhibitionsLookup` instance.
+type ExhibitionsLookupFunc = curatorial.LookupTableFunc[*Exhibition]
+
+// ExhibitionsLookup implements the `curatorial.TypedLookup` interface for `*Exhibition` records.
+type ExhibitionsLookup = curatorial.LookupTable[*Exhibition]
+
+var lookup_options = &curatorial.LookupTableOptions[*Exhibition]{
+	Filename: "exhibitions.json",
+	Keys:     lookupKeys,
+	Compile:  CompileExhibitionsData,
+	NotFound: func(code string) error {
+		return NotFound{code}
+	},
+	Name: func(data *Exhibition) string {
+		return data.Name
+	},
 }
 
-func init() {
-	ctx := context.Background()
-	curatorial.RegisterLookup(ctx, "exhibitions", NewLookup)
-	lookup_idx = int64(0)
-}
+// default_lookup is the lookup table derived from the precompiled (embedded) data that is shared by all the callers of `NewTypedLookup`
+// (and `NewLookup`) with an empty URI. It is created the first time it is needed.
+var default_lookup = sync.OnceValues(func() (curatorial.TypedLookup[*Exhibition], error) {
+	return curatorial.NewLookupTable(context.Background(), lookup_options, "")
+})
 
-// NewLookup will return an `curatorial.Lookup` instance. By default the lookup table is derived from precompiled (embedded) data in `data/exhibitions.json`
-// by passing in `exhibitions://` as the URI. It is also possible to create a new lookup table with the following URI options:
-//
-//	`exhibitions://github`
-//
-// This will cause the lookup table to be derived from the data stored at https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/exhibitions.json. This might be desirable if there have been updates to the underlying data that are not reflected in the locally installed package's pre-compiled data.
-//
-//	`exhibitions://iterator?uri={URI}&source={SOURCE}`
-//
-// This will cause the lookup table to be derived, at runtime, from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance. `{URI}` should be a valid `whosonfirst/go-whosonfirst-iterate/iterator` URI and `{SOURCE}` is one or more URIs for the iterator to process.
-func NewLookup(ctx context.Context, uri string) (curatorial.Lookup, error) {
+// default_data_complete reports whether the precompiled (embedded) data used by `default_lookup` contains the properties derived
+// by `CompileExhibitionsData` for galleries, dates, post-security and lineage. Data compiled before those properties were introduced
+// does not contain the `sfomuseum:post_security` property which is otherwise always present.
+var default_data_complete = sync.OnceValues(func() (bool, error) {
 
-	u, err := url.Parse(uri)
+	r, err := curatorial.OpenPrecompiledData(lookup_options.Filename)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return false, err
 	}
 
-	// Reminder: u.Scheme is used by the curatorial.Lookup constructor
-
-	switch u.Host {
-	case "iterator":
-
-		q := u.Query()
-
-		iterator_uri := q.Get("uri")
-		iterator_sources := q["source"]
+	defer r.Close()
 
-		return NewLookupFromIterator(ctx, iterator_uri, iterator_sources...)
+	return isCompleteData(r)
+})
 
-	case "github":
+// isCompleteData reports whether every record in the precompiled data in 'r' contains the `sfomuseum:post_security` property.
+func isCompleteData(r io.Reader) (bool, error) {
 
-		data_url := "https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/exhibitions.json"
-		rsp, err := http.Get(data_url)
+	var records []map[string]json.RawMessage
 
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load remote data from Github, %w", err)
-		}
+	err := json.NewDecoder(r).Decode(&records)
 
-		lookup_func := NewLookupFuncWithReader(ctx, rsp.Body)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
+	if err != nil {
+		return false, fmt.Errorf("Failed to decode precompiled data, %w", err)
+	}
 
-	default:
+	for _, rec := range records {
 
-		fs := data.FS
-		fh, err := fs.Open("exhibitions.json")
+		_, ok := rec["sfomuseum:post_security"]
 
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load local precompiled data, %w", err)
+		if !ok {
+			return false, nil
 		}
-
-		lookup_func := NewLookupFuncWithReader(ctx, fh)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
 	}
-}
 
-// NewLookup will return an `ExhibitionsLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
-// `r` will be closed when the `ExhibitionsLookupFunc` function instance is invoked.
-// It is assumed that the data in `r` will be formatted in the same way as the procompiled (embedded) data stored in `data/sfomuseum.json`.
-func NewLookupFuncWithReader(ctx context.Context, r io.ReadCloser) ExhibitionsLookupFunc {
-
-	defer r.Close()
+	return true, nil
+}
 
-	var exhibitions_list []*Exhibition
+// newCompleteDefaultLookup returns the lookup derived from the precompiled (embedded) data (see `NewLookup`) for use by methods
+// that query gallery, date, post-security or lineage properties. If the precompiled data does not contain those properties an
+// `IncompletePrecompiledData` error is returned rather than a lookup which would silently return no results.
+func newCompleteDefaultLookup(ctx context.Context) (curatorial.Lookup, error) {
 
-	dec := json.NewDecoder(r)
-	err := dec.Decode(&exhibitions_list)
+	complete, err := default_data_complete()
 
 	if err != nil {
+		return nil, err
+	}
 
-		lookup_func := func(ctx context.Context) {
-			lookup_init_err = err
-		}
-
-		return lookup_func
+	if !complete {
+		return nil, IncompletePrecompiledData{lookup_options.Filename}
 	}
 
-	return NewLookupFuncWithExhibitions(ctx, exhibitions_list)
+	return NewLookup(ctx, "")
 }
 
-// NewLookup will return an `ExhibitionsLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `exhibitions_list`.
-func NewLookupFuncWithExhibitions(ctx context.Context, exhibitions_list []*Exhibition) ExhibitionsLookupFunc {
-
-	lookup_func := func(ctx context.Context) {
-
-		table := new(sync.Map)
-
-		for _, data := range exhibitions_list {
+func init() {
+	ctx := context.Background()
+	curatorial.RegisterLookup(ctx, "exhibitions", NewLookup)
+}
 
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				// pass
-			}
+// NewLookup will return an `curatorial.Lookup` instance. By default the lookup table is derived from precompiled (embedded) data in `data/exhibitions.json`
+// by passing in `exhibitions://` as the URI. It is also possible to create a new lookup table with the following URI options:
+//
+//	`exhibitions://github`
+//
+// This will cause the lookup table to be derived from the data stored at https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/exhibitions.json. This might be desirable if there have been updates to the underlying data that are not reflected in the locally installed package's pre-compiled data.
+// If the remote data can not be retrieved the precompiled (embedded) data is used instead. Downloaded data can be cached, and revalidated, locally
+// using the `cache={DIRECTORY}` parameter and an alternate location (for example a mirror) can be specified using the `base-url={URL}` parameter.
+// See `curatorial.NewGitHubOptionsFromQuery` for details.
+//
+//	`exhibitions://iterator?uri={URI}&source={SOURCE}`
+//
+// This will cause the lookup table to be derived, at runtime, from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance. `{URI}` should be a valid `whosonfirst/go-whosonfirst-iterate/iterator` URI and `{SOURCE}` is one or more URIs for the iterator to process.
+//
+//	`exhibitions://file?path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file (for example one produced by the `compile-exhibitions-data` tool) stored at `{PATH}` on the local filesystem. If `{PATH}` ends in ".gz" the file is assumed to be gzip-compressed.
+//
+//	`exhibitions://reader?uri={URI}&path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file read from a `whosonfirst/go-reader` instance. `{URI}` should be a valid `whosonfirst/go-reader` URI and `{PATH}` is the path of the file to read, relative to that reader. If `{PATH}` is empty then "exhibitions.json" is used.
+//
+// Any of these URIs may also include a `?refresh={DURATION}` parameter (for example `exhibitions://iterator?uri={URI}&source={SOURCE}&refresh=1h`)
+// which will cause the lookup table to be reloaded from its source periodically. Lookups can also be reloaded on demand using `curatorial.RefreshLookup`.
+//
+// Methods that query gallery, date, post-security or lineage properties using the precompiled (embedded) data (for example `ExhibitionsInGallery`,
+// `FindExhibitionsOnView` or `Lineage`) return an `IncompletePrecompiledData` error if that data was compiled before those properties were introduced.
+//
+// If 'uri' is empty a lookup table derived from the precompiled (embedded) data is created once and shared by all subsequent callers (including convenience
+// methods like `FindCurrentExhibition`) rather than creating a new lookup table each time. All other URIs, including `exhibitions://`, create a new, independent lookup table.
+func NewLookup(ctx context.Context, uri string) (curatorial.Lookup, error) {
 
-			appendData(ctx, table, data)
-		}
+	l, err := NewTypedLookup(ctx, uri)
 
-		lookup_table = table
+	if err != nil {
+		return nil, err
 	}
 
-	return lookup_func
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-// NewLookupWithLookupFunc will return an `curatorial.Lookup` instance derived by data compiled using `lookup_func`.
-func NewLookupWithLookupFunc(ctx context.Context, lookup_func ExhibitionsLookupFunc) (curatorial.Lookup, error) {
+// NewTypedLookup will return an `curatorial.TypedLookup` instance for `*Exhibition` records. See `NewLookup` for details on the URIs supported.
+func NewTypedLookup(ctx context.Context, uri string) (curatorial.TypedLookup[*Exhibition], error) {
 
-	fn := func() {
-		lookup_func(ctx)
+	if uri == "" {
+		return default_lookup()
 	}
 
-	lookup_init.Do(fn)
+	return curatorial.NewLookupTable(ctx, lookup_options, uri)
+}
 
-	if lookup_init_err != nil {
-		return nil, lookup_init_err
-	}
+// NewLookupFuncWithReader will return an `ExhibitionsLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
+// `r` will be closed when the `ExhibitionsLookupFunc` function instance is invoked.
+// It is assumed that the data in `r` will be formatted in the same way as the precompiled (embedded) data stored in `data/exhibitions.json`.
+func NewLookupFuncWithReader(ctx context.Context, r io.ReadCloser) ExhibitionsLookupFunc {
+	return curatorial.NewLookupTableFuncWithReader[*Exhibition](ctx, r)
+}
 
-	l := ExhibitionsLookup{}
-	return &l, nil
+// NewLookupFuncWithExhibitions will return an `ExhibitionsLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `exhibitions_list`.
+func NewLookupFuncWithExhibitions(ctx context.Context, exhibitions_list []*Exhibition) ExhibitionsLookupFunc {
+	return curatorial.NewLookupTableFuncWithRecords(ctx, exhibitions_list)
 }
 
-func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
+// NewLookupWithLookupFunc will return an `curatorial.Lookup` instance derived by data compiled using `lookup_func`.
+func NewLookupWithLookupFunc(ctx context.Context, lookup_func ExhibitionsLookupFunc) (curatorial.Lookup, error) {
 
-	exhibitions_list, err := CompileExhibitionsData(ctx, iterator_uri, iterator_sources...)
+	l, err := NewTypedLookupWithLookupFunc(ctx, lookup_func)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to compile exhibitions data, %w", err)
+		return nil, err
 	}
 
-	lookup_func := NewLookupFuncWithExhibitions(ctx, exhibitions_list)
-	return NewLookupWithLookupFunc(ctx, lookup_func)
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *ExhibitionsLookup) Find(ctx context.Context, code string) ([]interface{}, error) {
-
-	pointers, ok := lookup_table.Load(code)
-
-	if !ok {
-		return nil, NotFound{code}
-	}
-
-	candidates := make([]interface{}, 0)
-
-	for _, p := range pointers.([]string) {
+// NewTypedLookupWithLookupFunc will return an `curatorial.TypedLookup` instance derived by data compiled using `lookup_func`.
+func NewTypedLookupWithLookupFunc(ctx context.Context, lookup_func ExhibitionsLookupFunc) (curatorial.TypedLookup[*Exhibition], error) {
+	return curatorial.NewLookupTableWithLookupFunc(ctx, lookup_options, lookup_func)
+}
 
-		if !strings.HasPrefix(p, "pointer:") {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
+// NewLookupFromIterator will return an `curatorial.Lookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
 
-		row, ok := lookup_table.Load(p)
+	l, err := NewTypedLookupFromIterator(ctx, iterator_uri, iterator_sources...)
 
-		if !ok {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
-
-		candidates = append(candidates, row.(*Exhibition))
+	if err != nil {
+		return nil, err
 	}
 
-	return candidates, nil
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *ExhibitionsLookup) Append(ctx context.Context, data interface{}) error {
-	return appendData(ctx, lookup_table, data.(*Exhibition))
+// NewTypedLookupFromIterator will return an `curatorial.TypedLookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewTypedLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.TypedLookup[*Exhibition], error) {
+	return curatorial.NewLookupTableFromIterator(ctx, lookup_options, iterator_uri, iterator_sources...)
 }
 
-func appendData(ctx context.Context, table *sync.Map, data *Exhibition) error {
-
-	idx := atomic.AddInt64(&lookup_idx, 1)
-
-	pointer := fmt.Sprintf("pointer:%d", idx)
-	table.Store(pointer, data)
+// lookupKeys returns the list of codes that 'data' should be indexed by.
+func lookupKeys(data *Exhibition) []string {
 
 	str_wofid := strconv.FormatInt(data.WhosOnFirstId, 10)
 	str_sfomid := strconv.FormatInt(data.SFOMuseumId, 10)
@@ -222,37 +212,9 @@ func appendData(ctx context.Context, table *sync.Map, data *Exhibition) error {
 		possible_codes = append(possible_codes, fmt.Sprintf("sfomuseum_www:exhibition_id=%s", str_wwwid))
 	}
 
-	for _, code := range possible_codes {
-
-		if code == "" {
-			continue
-		}
-
-		pointers := make([]string, 0)
-		has_pointer := false
-
-		others, ok := table.Load(code)
-
-		if ok {
-
-			pointers = others.([]string)
-		}
-
-		for _, dupe := range pointers {
-
-			if dupe == pointer {
-				has_pointer = true
-				break
-			}
-		}
-
-		if has_pointer {
-			continue
-		}
-
-		pointers = append(pointers, pointer)
-		table.Store(code, pointers)
+	for _, gallery_id := range data.GalleryIds {
+		possible_codes = append(possible_codes, galleryCode(gallery_id))
 	}
 
-	return nil
+	return possible_codes
 }
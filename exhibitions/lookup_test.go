@@ -2,8 +2,15 @@ package exhibitions
 
 import (
 	"context"
-	"github.com/sfomuseum/go-sfomuseum-curatorial"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
 )
 
 func TestExhibitionsLookup(t *testing.T) {
@@ -48,3 +55,218 @@ func TestExhibitionsLookup(t *testing.T) {
 	}
 
 }
+
+func TestIndependentLookups(t *testing.T) {
+
+	ctx := context.Background()
+
+	embedded_lu, err := NewLookup(ctx, "exhibitions://")
+
+	if err != nil {
+		t.Fatalf("Failed to create embedded lookup, %v", err)
+	}
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 999999, Name: "Testing"},
+	}
+
+	lookup_func := NewLookupFuncWithExhibitions(ctx, exhibitions_list)
+	custom_lu, err := NewLookupWithLookupFunc(ctx, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create custom lookup, %v", err)
+	}
+
+	_, err = custom_lu.Find(ctx, "1845")
+
+	if !IsNotFound(err) {
+		t.Fatalf("Expected custom lookup to not find '1845'")
+	}
+
+	_, err = embedded_lu.Find(ctx, "999999")
+
+	if !IsNotFound(err) {
+		t.Fatalf("Expected embedded lookup to not find '999999'")
+	}
+
+	_, err = embedded_lu.Find(ctx, "1845")
+
+	if err != nil {
+		t.Fatalf("Expected embedded lookup to find '1845', %v", err)
+	}
+
+	_, err = custom_lu.Find(ctx, "999999")
+
+	if err != nil {
+		t.Fatalf("Expected custom lookup to find '999999', %v", err)
+	}
+}
+
+func TestDefaultLookup(t *testing.T) {
+
+	ctx := context.Background()
+
+	a, err := NewTypedLookup(ctx, "")
+
+	if err != nil {
+		t.Fatalf("Failed to create default lookup, %v", err)
+	}
+
+	b, err := NewTypedLookup(ctx, "")
+
+	if err != nil {
+		t.Fatalf("Failed to create default lookup, %v", err)
+	}
+
+	if a != b {
+		t.Fatalf("Expected default lookups to share the same lookup table")
+	}
+
+	c, err := NewTypedLookup(ctx, "exhibitions://")
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup, %v", err)
+	}
+
+	if a == c {
+		t.Fatalf("Expected explicit URI to create an independent lookup table")
+	}
+}
+
+func TestIsCompleteData(t *testing.T) {
+
+	tests := map[string]bool{
+		`[]`: true,
+		`[{"wof:id":1,"sfomuseum:post_security":-1},{"wof:id":2,"sfomuseum:post_security":1}]`: true,
+		`[{"wof:id":1,"sfomuseum:post_security":-1},{"wof:id":2}]`:                             false,
+	}
+
+	for data, expected := range tests {
+
+		complete, err := isCompleteData(strings.NewReader(data))
+
+		if err != nil {
+			t.Fatalf("Failed to check %s, %v", data, err)
+		}
+
+		if complete != expected {
+			t.Fatalf("Unexpected result for %s, expected %t", data, expected)
+		}
+	}
+}
+
+func TestIncompletePrecompiledData(t *testing.T) {
+
+	ctx := context.Background()
+
+	complete, err := default_data_complete()
+
+	if err != nil {
+		t.Fatalf("Failed to check precompiled data, %v", err)
+	}
+
+	_, err = ExhibitionsInGallery(ctx, 1745882083)
+
+	switch {
+	case complete && err != nil:
+		t.Fatalf("Failed to find exhibitions in gallery, %v", err)
+	case !complete && !IsIncompletePrecompiledData(err):
+		t.Fatalf("Expected IncompletePrecompiledData error, got %v", err)
+	}
+}
+
+func TestTypedLookup(t *testing.T) {
+
+	ctx := context.Background()
+
+	lu, err := NewTypedLookup(ctx, "exhibitions://")
+
+	if err != nil {
+		t.Fatalf("Failed to create typed lookup, %v", err)
+	}
+
+	results, err := lu.Find(ctx, "1845")
+
+	if err != nil {
+		t.Fatalf("Failed to find '1845', %v", err)
+	}
+
+	if len(results) != 1 || results[0].WhosOnFirstId != 1746382277 {
+		t.Fatalf("Invalid results for '1845'")
+	}
+
+	count := 0
+
+	for _, err := range lu.Iterate(ctx) {
+
+		if err != nil {
+			t.Fatalf("Failed to iterate lookup, %v", err)
+		}
+
+		count += 1
+	}
+
+	if count == 0 {
+		t.Fatalf("Expected iterator to yield records")
+	}
+}
+
+func TestExhibitionsLookupWithFile(t *testing.T) {
+
+	ctx := context.Background()
+
+	exhibitions_list := []*Exhibition{
+		&Exhibition{WhosOnFirstId: 1, SFOMuseumId: 999999, Name: "Testing"},
+	}
+
+	root := t.TempDir()
+	path := filepath.Join(root, "exhibitions.json")
+
+	enc_body, err := json.Marshal(exhibitions_list)
+
+	if err != nil {
+		t.Fatalf("Failed to marshal exhibitions, %v", err)
+	}
+
+	err = os.WriteFile(path, enc_body, 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write %s, %v", path, err)
+	}
+
+	q := url.Values{}
+	q.Set("path", path)
+
+	reader_q := url.Values{}
+	reader_q.Set("uri", fmt.Sprintf("fs://%s", root))
+
+	schemes := []string{
+		fmt.Sprintf("exhibitions://file?%s", q.Encode()),
+		fmt.Sprintf("exhibitions://reader?%s", reader_q.Encode()),
+	}
+
+	for _, s := range schemes {
+
+		lu, err := curatorial.NewLookup(ctx, s)
+
+		if err != nil {
+			t.Fatalf("Failed to create lookup for '%s', %v", s, err)
+		}
+
+		results, err := lu.Find(ctx, "999999")
+
+		if err != nil {
+			t.Fatalf("Unable to find '999999' using scheme '%s', %v", s, err)
+		}
+
+		if len(results) != 1 {
+			t.Fatalf("Invalid results for '999999' using scheme '%s'", s)
+		}
+
+		_, err = lu.Find(ctx, "1845")
+
+		if !IsNotFound(err) {
+			t.Fatalf("Expected '1845' to not be found using scheme '%s'", s)
+		}
+	}
+}
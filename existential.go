@@ -0,0 +1,169 @@
+package curatorial
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-feature/properties"
+)
+
+// ExistentialState is a value used to select records by their existential (is current, is deprecated, etc.) state.
+type ExistentialState int
+
+const (
+	// Records whose `mz:is_current` property is 1.
+	StateCurrent ExistentialState = iota
+	// Records whose `mz:is_current` property is 0.
+	StateNotCurrent
+	// Records whose `mz:is_current` property is -1.
+	StateUnknown
+	// Records that have been deprecated.
+	StateDeprecated
+	// Records that have ceased.
+	StateCeased
+	// Records that have been superseded by another record.
+	StateSuperseded
+)
+
+var existential_states = map[ExistentialState]string{
+	StateCurrent:    "current",
+	StateNotCurrent: "not-current",
+	StateUnknown:    "unknown",
+	StateDeprecated: "deprecated",
+	StateCeased:     "ceased",
+	StateSuperseded: "superseded",
+}
+
+func (s ExistentialState) String() string {
+
+	label, ok := existential_states[s]
+
+	if !ok {
+		return fmt.Sprintf("ExistentialState(%d)", int(s))
+	}
+
+	return label
+}
+
+// ParseExistentialState returns the `ExistentialState` for 'label' which is expected to be one of: current, not-current,
+// unknown, deprecated, ceased or superseded.
+func ParseExistentialState(label string) (ExistentialState, error) {
+
+	label = strings.ToLower(strings.TrimSpace(label))
+
+	for s, l := range existential_states {
+
+		if l == label {
+			return s, nil
+		}
+	}
+
+	return -1, fmt.Errorf("Invalid existential state '%s'", label)
+}
+
+// ExistentialFlags contains the existential properties of a record. Each value is one of 1 (true), 0 (false) or -1 (unknown).
+type ExistentialFlags struct {
+	IsCurrent    int64
+	IsDeprecated int64
+	IsCeased     int64
+	IsSuperseded int64
+}
+
+// ExistentialRecord is an interface for records that can report their existential state.
+type ExistentialRecord interface {
+	// ExistentialFlags returns the existential properties of the record.
+	ExistentialFlags() *ExistentialFlags
+}
+
+// HasState returns a boolean value indicating whether 'f' is in state 's'.
+func (f *ExistentialFlags) HasState(s ExistentialState) bool {
+
+	switch s {
+	case StateCurrent:
+		return f.IsCurrent == 1
+	case StateNotCurrent:
+		return f.IsCurrent == 0
+	case StateUnknown:
+		return f.IsCurrent == -1
+	case StateDeprecated:
+		return f.IsDeprecated == 1
+	case StateCeased:
+		return f.IsCeased == 1
+	case StateSuperseded:
+		return f.IsSuperseded == 1
+	default:
+		return false
+	}
+}
+
+// MatchesExistentialStates returns a boolean value indicating whether 'r' is in any of 'states'. If 'states' is empty then
+// all records match.
+func MatchesExistentialStates(r ExistentialRecord, states ...ExistentialState) bool {
+
+	if len(states) == 0 {
+		return true
+	}
+
+	f := r.ExistentialFlags()
+
+	for _, s := range states {
+
+		if f.HasState(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeriveExistentialFlags derives the existential properties of the Who's On First record 'body'.
+func DeriveExistentialFlags(body []byte) (*ExistentialFlags, error) {
+
+	is_current, err := properties.IsCurrent(body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive is current, %w", err)
+	}
+
+	is_ceased, err := properties.IsCeased(body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive is ceased, %w", err)
+	}
+
+	is_superseded, err := properties.IsSuperseded(body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive is superseded, %w", err)
+	}
+
+	f := &ExistentialFlags{
+		IsCurrent:    is_current.Flag(),
+		IsDeprecated: isDeprecated(body),
+		IsCeased:     is_ceased.Flag(),
+		IsSuperseded: is_superseded.Flag(),
+	}
+
+	return f, nil
+}
+
+// isDeprecated derives the is deprecated flag for 'body'. This is done locally, rather than using `properties.IsDeprecated`,
+// because records without an (or with an empty) `edtf:deprecated` property need to be treated as not deprecated rather than unknown.
+func isDeprecated(body []byte) int64 {
+
+	rsp := gjson.GetBytes(body, "properties.edtf:deprecated")
+
+	if !rsp.Exists() {
+		return 0
+	}
+
+	switch rsp.String() {
+	case "", "-":
+		return 0
+	case "u", "uuuu":
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,79 @@
+package curatorial
+
+import (
+	"testing"
+)
+
+func TestDeriveExistentialFlags(t *testing.T) {
+
+	tests := map[string]ExistentialFlags{
+		`{"properties":{"mz:is_current":1}}`:                                                      ExistentialFlags{IsCurrent: 1, IsCeased: -1},
+		`{"properties":{"mz:is_current":-1,"edtf:cessation":".."}}`:                               ExistentialFlags{IsCurrent: -1},
+		`{"properties":{"edtf:cessation":"2019-06-01"}}`:                                          ExistentialFlags{IsCurrent: 0, IsCeased: 1},
+		`{"properties":{"edtf:deprecated":"2020-01-01","edtf:cessation":".."}}`:                   ExistentialFlags{IsCurrent: 0, IsDeprecated: 1},
+		`{"properties":{"mz:is_current":0,"wof:superseded_by":[123],"edtf:cessation":"2019-06"}}`: ExistentialFlags{IsCurrent: 0, IsCeased: 1, IsSuperseded: 1},
+	}
+
+	for body, expected := range tests {
+
+		f, err := DeriveExistentialFlags([]byte(body))
+
+		if err != nil {
+			t.Fatalf("Failed to derive existential flags for %s, %v", body, err)
+		}
+
+		if *f != expected {
+			t.Fatalf("Unexpected flags for %s, expected %v but got %v", body, expected, *f)
+		}
+	}
+}
+
+type testExistentialRecord struct {
+	flags *ExistentialFlags
+}
+
+func (r *testExistentialRecord) ExistentialFlags() *ExistentialFlags {
+	return r.flags
+}
+
+func TestMatchesExistentialStates(t *testing.T) {
+
+	r := &testExistentialRecord{
+		flags: &ExistentialFlags{IsCurrent: -1, IsCeased: 1},
+	}
+
+	if !MatchesExistentialStates(r) {
+		t.Fatalf("Expected record to match empty states")
+	}
+
+	if MatchesExistentialStates(r, StateCurrent) {
+		t.Fatalf("Did not expect record to match current state")
+	}
+
+	if !MatchesExistentialStates(r, StateCurrent, StateUnknown) {
+		t.Fatalf("Expected record to match current or unknown states")
+	}
+
+	if !MatchesExistentialStates(r, StateCeased) {
+		t.Fatalf("Expected record to match ceased state")
+	}
+
+	for _, label := range []string{"current", "not-current", "unknown", "deprecated", "ceased", "superseded"} {
+
+		s, err := ParseExistentialState(label)
+
+		if err != nil {
+			t.Fatalf("Failed to parse '%s', %v", label, err)
+		}
+
+		if s.String() != label {
+			t.Fatalf("Unexpected label for '%s', %s", label, s.String())
+		}
+	}
+
+	_, err := ParseExistentialState("bogus")
+
+	if err == nil {
+		t.Fatalf("Expected error parsing 'bogus'")
+	}
+}
@@ -0,0 +1,264 @@
+package curatorial
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitHubOptions defines configuration details for loading precompiled lookup data from GitHub (or a mirror).
+type GitHubOptions struct {
+	// The base URL that precompiled data files are fetched from. Default is `GITHUB_DATA_URL`.
+	BaseURL string
+	// An optional directory where downloaded data (and its ETag header) are cached. If present cached data will be
+	// revalidated using a conditional ("If-None-Match") request.
+	CacheDir string
+	// The maximum amount of time to wait for a response. Default is 30 seconds.
+	Timeout time.Duration
+	// If true and the remote data can not be retrieved then cached data, or failing that precompiled (embedded) data, will be used instead.
+	Fallback bool
+}
+
+// DefaultGitHubOptions returns a `GitHubOptions` instance with default values.
+func DefaultGitHubOptions() *GitHubOptions {
+
+	opts := &GitHubOptions{
+		BaseURL:  GITHUB_DATA_URL,
+		Timeout:  30 * time.Second,
+		Fallback: true,
+	}
+
+	return opts
+}
+
+// NewGitHubOptionsFromQuery returns a `GitHubOptions` instance derived from the following query parameters:
+//
+//   - `base-url` The base URL that precompiled data files are fetched from.
+//   - `cache` A directory where downloaded data is cached.
+//   - `timeout` The maximum amount of time to wait for a response, as a valid `time.ParseDuration` string.
+//   - `fallback` A boolean flag indicating whether to fall back to cached or precompiled (embedded) data if the remote data can not be retrieved.
+func NewGitHubOptionsFromQuery(q url.Values) (*GitHubOptions, error) {
+
+	opts := DefaultGitHubOptions()
+
+	if q.Has("base-url") {
+
+		base_url := q.Get("base-url")
+
+		if !strings.HasSuffix(base_url, "/") {
+			base_url = base_url + "/"
+		}
+
+		opts.BaseURL = base_url
+	}
+
+	if q.Has("cache") {
+		opts.CacheDir = q.Get("cache")
+	}
+
+	if q.Has("timeout") {
+
+		d, err := time.ParseDuration(q.Get("timeout"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?timeout= parameter, %w", err)
+		}
+
+		opts.Timeout = d
+	}
+
+	if q.Has("fallback") {
+
+		v, err := strconv.ParseBool(q.Get("fallback"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?fallback= parameter, %w", err)
+		}
+
+		opts.Fallback = v
+	}
+
+	return opts, nil
+}
+
+func newLookupTableSourceWithGitHub[T any](options *LookupTableOptions[T], gh_opts *GitHubOptions) LookupTableSourceFunc[T] {
+
+	embedded_source := newLookupTableSourceWithEmbeddedData(options)
+
+	source := func(ctx context.Context) (LookupTableFunc[T], error) {
+
+		r, remote_err := fetchGitHubData(ctx, gh_opts, options.Filename)
+
+		if remote_err != nil {
+
+			if !gh_opts.Fallback {
+				return nil, fmt.Errorf("Failed to load remote data from Github, %w", remote_err)
+			}
+
+			cached_r, err := openCachedGitHubData(gh_opts, options.Filename)
+
+			if err != nil {
+				slog.Warn("Failed to load remote data from Github, using precompiled data", "filename", options.Filename, "error", remote_err)
+				return embedded_source(ctx)
+			}
+
+			slog.Warn("Failed to load remote data from Github, using cached data", "filename", options.Filename, "error", remote_err)
+			r = cached_r
+		}
+
+		data_r, err := newDataReader(r, options.Filename)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read remote data from Github, %w", err)
+		}
+
+		return NewLookupTableFuncWithReader[T](ctx, data_r), nil
+	}
+
+	return source
+}
+
+// fetchGitHubData retrieves 'filename' relative to the base URL defined in 'gh_opts'. If a cache directory is defined then
+// the data will be written to (or, if unchanged, read from) that directory.
+func fetchGitHubData(ctx context.Context, gh_opts *GitHubOptions, filename string) (io.ReadCloser, error) {
+
+	data_url := gh_opts.BaseURL + filename
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, data_url, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request, %w", err)
+	}
+
+	var cache_path string
+	var etag_path string
+
+	if gh_opts.CacheDir != "" {
+
+		cache_path = filepath.Join(gh_opts.CacheDir, filename)
+		etag_path = cache_path + ".etag"
+
+		_, err := os.Stat(cache_path)
+
+		if err == nil {
+
+			etag, err := os.ReadFile(etag_path)
+
+			if err == nil && len(etag) > 0 {
+				req.Header.Set("If-None-Match", string(bytes.TrimSpace(etag)))
+			}
+		}
+	}
+
+	cl := &http.Client{
+		Timeout: gh_opts.Timeout,
+	}
+
+	rsp, err := cl.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		// pass
+	case http.StatusNotModified:
+
+		rsp.Body.Close()
+
+		if cache_path == "" {
+			return nil, UnexpectedStatus{data_url, rsp.StatusCode}
+		}
+
+		return os.Open(cache_path)
+
+	default:
+		rsp.Body.Close()
+		return nil, UnexpectedStatus{data_url, rsp.StatusCode}
+	}
+
+	if cache_path == "" {
+		return rsp.Body, nil
+	}
+
+	defer rsp.Body.Close()
+
+	err = writeCachedGitHubData(cache_path, rsp.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to cache %s, %w", data_url, err)
+	}
+
+	etag := rsp.Header.Get("ETag")
+
+	if etag != "" {
+
+		err = os.WriteFile(etag_path, []byte(etag), 0644)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to cache ETag for %s, %w", data_url, err)
+		}
+
+	} else {
+		os.Remove(etag_path)
+	}
+
+	return os.Open(cache_path)
+}
+
+// writeCachedGitHubData writes the contents of 'r' to a temporary file which is then renamed to 'cache_path'
+// so that partial downloads never replace previously cached data.
+func writeCachedGitHubData(cache_path string, r io.Reader) error {
+
+	root := filepath.Dir(cache_path)
+
+	err := os.MkdirAll(root, 0755)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create cache directory, %w", err)
+	}
+
+	tmp_fh, err := os.CreateTemp(root, filepath.Base(cache_path)+".*.tmp")
+
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary file, %w", err)
+	}
+
+	tmp_path := tmp_fh.Name()
+	defer os.Remove(tmp_path)
+
+	_, err = io.Copy(tmp_fh, r)
+
+	if err != nil {
+		tmp_fh.Close()
+		return fmt.Errorf("Failed to write temporary file, %w", err)
+	}
+
+	err = tmp_fh.Close()
+
+	if err != nil {
+		return fmt.Errorf("Failed to close temporary file, %w", err)
+	}
+
+	return os.Rename(tmp_path, cache_path)
+}
+
+// openCachedGitHubData opens the cached copy of 'filename', if it exists.
+func openCachedGitHubData(gh_opts *GitHubOptions, filename string) (io.ReadCloser, error) {
+
+	if gh_opts.CacheDir == "" {
+		return nil, fmt.Errorf("No cache directory defined")
+	}
+
+	return os.Open(filepath.Join(gh_opts.CacheDir, filename))
+}
@@ -0,0 +1,149 @@
+package curatorial
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGitHubLookupTable(t *testing.T) {
+
+	ctx := context.Background()
+
+	var requests int64
+	var not_modified int64
+
+	handler := func(rsp http.ResponseWriter, req *http.Request) {
+
+		atomic.AddInt64(&requests, 1)
+
+		switch req.URL.Path {
+		case "/exhibitions.json":
+			// pass
+		default:
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		etag := `"test"`
+
+		if req.Header.Get("If-None-Match") == etag {
+			atomic.AddInt64(&not_modified, 1)
+			rsp.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		rsp.Header().Set("ETag", etag)
+		rsp.Write([]byte(`[{"wof:id":1,"wof:name":"one"}]`))
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Filename: "exhibitions.json",
+		Keys: func(r *testRecord) []string {
+			return []string{r.Name}
+		},
+	}
+
+	q := url.Values{}
+	q.Set("base-url", s.URL)
+	q.Set("cache", t.TempDir())
+
+	uri := fmt.Sprintf("test://github?%s", q.Encode())
+
+	lu, err := NewLookupTable(ctx, opts, uri)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table for %s, %v", uri, err)
+	}
+
+	_, err = lu.Find(ctx, "one")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'one', %v", err)
+	}
+
+	err = lu.Refresh(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to refresh lookup table, %v", err)
+	}
+
+	_, err = lu.Find(ctx, "one")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'one' after refreshing, %v", err)
+	}
+
+	if atomic.LoadInt64(&requests) != 2 || atomic.LoadInt64(&not_modified) != 1 {
+		t.Fatalf("Expected cached data to be revalidated, requests: %d not modified: %d", requests, not_modified)
+	}
+
+	// Unexpected status codes with and without fallback
+
+	missing_opts := &LookupTableOptions[*testRecord]{
+		Filename: "missing.json",
+		Keys:     opts.Keys,
+	}
+
+	q = url.Values{}
+	q.Set("base-url", s.URL)
+	q.Set("fallback", "false")
+
+	uri = fmt.Sprintf("test://github?%s", q.Encode())
+
+	_, err = NewLookupTable(ctx, missing_opts, uri)
+
+	if !IsUnexpectedStatus(err) {
+		t.Fatalf("Expected UnexpectedStatus error, got %v", err)
+	}
+
+	q.Set("fallback", "true")
+
+	uri = fmt.Sprintf("test://github?%s", q.Encode())
+
+	_, err = NewLookupTable(ctx, missing_opts, uri)
+
+	if !IsNoPrecompiledData(err) {
+		t.Fatalf("Expected NoPrecompiledData error, got %v", err)
+	}
+}
+
+func TestGitHubLookupTableFallback(t *testing.T) {
+
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.NotFoundHandler())
+	base_url := s.URL
+	s.Close()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Filename: "exhibitions.json",
+		Keys: func(r *testRecord) []string {
+			return []string{fmt.Sprintf("%d", r.Id)}
+		},
+	}
+
+	q := url.Values{}
+	q.Set("base-url", base_url)
+
+	uri := fmt.Sprintf("test://github?%s", q.Encode())
+
+	lu, err := NewLookupTable(ctx, opts, uri)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table with fallback for %s, %v", uri, err)
+	}
+
+	_, err = lu.Find(ctx, "1746382277")
+
+	if err != nil {
+		t.Fatalf("Expected to find record in precompiled data, %v", err)
+	}
+}
@@ -4,6 +4,9 @@ go 1.25.0
 
 require (
 	github.com/aaronland/go-roster v1.0.0
+	github.com/aaronland/go-uid v0.5.0
+	github.com/paulmach/orb v0.11.1
+	github.com/sfomuseum/go-edtf v1.2.1
 	github.com/sfomuseum/go-flags v0.11.0
 	github.com/sfomuseum/go-sfomuseum-writer/v3 v3.0.5
 	github.com/tidwall/gjson v1.18.0
@@ -23,7 +26,6 @@ require (
 	github.com/aaronland/go-json-query v0.1.6 // indirect
 	github.com/aaronland/go-pool/v2 v2.0.0 // indirect
 	github.com/aaronland/go-string v1.0.0 // indirect
-	github.com/aaronland/go-uid v0.5.0 // indirect
 	github.com/aaronland/go-uid-artisanal v0.0.5 // indirect
 	github.com/aaronland/go-uid-proxy v0.4.1 // indirect
 	github.com/aaronland/go-uid-whosonfirst v0.0.7 // indirect
@@ -35,8 +37,6 @@ require (
 	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/natefinch/atomic v1.0.1 // indirect
-	github.com/paulmach/orb v0.11.1 // indirect
-	github.com/sfomuseum/go-edtf v1.2.1 // indirect
 	github.com/sfomuseum/go-sfomuseum-export/v3 v3.0.0 // indirect
 	github.com/tidwall/geoindex v1.4.4 // indirect
 	github.com/tidwall/geojson v1.4.5 // indirect
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/sfomuseum/go-sfomuseum-curatorial"
 	"github.com/tidwall/gjson"
-	"github.com/whosonfirst/go-whosonfirst-feature/properties"
 	"github.com/whosonfirst/go-whosonfirst-iterate/v3"
 	"github.com/whosonfirst/go-whosonfirst-uri"
 )
@@ -22,13 +22,13 @@ func CompilePublicArtWorksData(ctx context.Context, iterator_uri string, iterato
 	}
 
 	for rec, err := range iter.Iterate(ctx, iterator_sources...) {
-		
+
 		if err != nil {
 			return nil, fmt.Errorf("Failed to iterate sources, %w", err)
 		}
 
 		defer rec.Body.Close()
-	
+
 		select {
 		case <-ctx.Done():
 			break
@@ -67,17 +67,20 @@ func CompilePublicArtWorksData(ctx context.Context, iterator_uri string, iterato
 
 		name_rsp := gjson.GetBytes(body, "properties.wof:name")
 
-		is_current, err := properties.IsCurrent(body)
+		existential_flags, err := curatorial.DeriveExistentialFlags(body)
 
 		if err != nil {
-			return nil, fmt.Errorf("Failed to derive is current for %s, %w", rec.Path, err)
+			return nil, fmt.Errorf("Failed to derive existential flags for %s, %w", rec.Path, err)
 		}
 
 		w := &PublicArtWork{
 			WhosOnFirstId: wofid_rsp.Int(),
 			SFOMuseumId:   sfomid_rsp.Int(),
 			Name:          name_rsp.String(),
-			IsCurrent:     is_current.Flag(),
+			IsCurrent:     existential_flags.IsCurrent,
+			IsDeprecated:  existential_flags.IsDeprecated,
+			IsCeased:      existential_flags.IsCeased,
+			IsSuperseded:  existential_flags.IsSuperseded,
 		}
 
 		mapid_rsp := gjson.GetBytes(body, "properties.sfomuseum:map_id")
@@ -2,36 +2,41 @@ package publicart
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
 	"sync"
-	"sync/atomic"
 
 	"github.com/sfomuseum/go-sfomuseum-curatorial"
-	"github.com/sfomuseum/go-sfomuseum-curatorial/data"
 )
 
-var lookup_table *sync.Map
-var lookup_idx int64
-
-var lookup_init sync.Once
-var lookup_init_err error
-
-type PublicArtLookupFunc func(context.Context)
-
-type PublicArtLookup struct {
-	curatorial.Lookup
+// PublicArtLookupFunc is a function that populates a `PublicArtLookup` instance.
+type PublicArtLookupFunc = curatorial.LookupTableFunc[*PublicArtWork]
+
+// PublicArtLookup implements the `curatorial.TypedLookup` interface for `*PublicArtWork` records.
+type PublicArtLookup = curatorial.LookupTable[*PublicArtWork]
+
+var lookup_options = &curatorial.LookupTableOptions[*PublicArtWork]{
+	Filename: "publicart.json",
+	Keys:     lookupKeys,
+	Compile:  CompilePublicArtWorksData,
+	NotFound: func(code string) error {
+		return NotFound{code}
+	},
+	Name: func(data *PublicArtWork) string {
+		return data.Name
+	},
 }
 
+// default_lookup is the lookup table derived from the precompiled (embedded) data that is shared by all the callers of `NewTypedLookup`
+// (and `NewLookup`) with an empty URI. It is created the first time it is needed.
+var default_lookup = sync.OnceValues(func() (curatorial.TypedLookup[*PublicArtWork], error) {
+	return curatorial.NewLookupTable(context.Background(), lookup_options, "")
+})
+
 func init() {
 	ctx := context.Background()
 	curatorial.RegisterLookup(ctx, "publicart", NewLookup)
-	lookup_idx = int64(0)
 }
 
 // NewLookup will return an `curatorial.Lookup` instance. By default the lookup table is derived from precompiled (embedded) data in `data/publicart.json`
@@ -40,172 +45,96 @@ func init() {
 //	`publicart://github`
 //
 // This will cause the lookup table to be derived from the data stored at https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/publicart.json. This might be desirable if there have been updates to the underlying data that are not reflected in the locally installed package's pre-compiled data.
+// If the remote data can not be retrieved the precompiled (embedded) data is used instead. Downloaded data can be cached, and revalidated, locally
+// using the `cache={DIRECTORY}` parameter and an alternate location (for example a mirror) can be specified using the `base-url={URL}` parameter.
+// See `curatorial.NewGitHubOptionsFromQuery` for details.
 //
 //	`publicart://iterator?uri={URI}&source={SOURCE}`
 //
 // This will cause the lookup table to be derived, at runtime, from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance. `{URI}` should be a valid `whosonfirst/go-whosonfirst-iterate/iterator` URI and `{SOURCE}` is one or more URIs for the iterator to process.
+//
+//	`publicart://file?path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file (for example one produced by the `compile-publicart-data` tool) stored at `{PATH}` on the local filesystem. If `{PATH}` ends in ".gz" the file is assumed to be gzip-compressed.
+//
+//	`publicart://reader?uri={URI}&path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file read from a `whosonfirst/go-reader` instance. `{URI}` should be a valid `whosonfirst/go-reader` URI and `{PATH}` is the path of the file to read, relative to that reader. If `{PATH}` is empty then "publicart.json" is used.
+//
+// Any of these URIs may also include a `?refresh={DURATION}` parameter (for example `publicart://iterator?uri={URI}&source={SOURCE}&refresh=1h`)
+// which will cause the lookup table to be reloaded from its source periodically. Lookups can also be reloaded on demand using `curatorial.RefreshLookup`.
+//
+// If 'uri' is empty a lookup table derived from the precompiled (embedded) data is created once and shared by all subsequent callers (including convenience
+// methods like `FindCurrentPublicArtWork`) rather than creating a new lookup table each time. All other URIs, including `publicart://`, create a new, independent lookup table.
 func NewLookup(ctx context.Context, uri string) (curatorial.Lookup, error) {
 
-	u, err := url.Parse(uri)
+	l, err := NewTypedLookup(ctx, uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, err
 	}
 
-	// Reminder: u.Scheme is used by the curatorial.Lookup constructor
-
-	switch u.Host {
-	case "iterator":
-
-		q := u.Query()
-
-		iterator_uri := q.Get("uri")
-		iterator_sources := q["source"]
-
-		return NewLookupFromIterator(ctx, iterator_uri, iterator_sources...)
-
-	case "github":
-
-		data_url := "https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/publicart.json"
-		rsp, err := http.Get(data_url)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load remote data from Github, %w", err)
-		}
-
-		lookup_func := NewLookupFuncWithReader(ctx, rsp.Body)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
-
-	default:
-
-		fs := data.FS
-		fh, err := fs.Open("publicart.json")
+	return curatorial.NewUntypedLookup(l), nil
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load local precompiled data, %w", err)
-		}
+// NewTypedLookup will return an `curatorial.TypedLookup` instance for `*PublicArtWork` records. See `NewLookup` for details on the URIs supported.
+func NewTypedLookup(ctx context.Context, uri string) (curatorial.TypedLookup[*PublicArtWork], error) {
 
-		lookup_func := NewLookupFuncWithReader(ctx, fh)
-		return NewLookupWithLookupFunc(ctx, lookup_func)
+	if uri == "" {
+		return default_lookup()
 	}
+
+	return curatorial.NewLookupTable(ctx, lookup_options, uri)
 }
 
-// NewLookup will return an `PublicArtLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
+// NewLookupFuncWithReader will return an `PublicArtLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `r`.
 // `r` will be closed when the `PublicArtLookupFunc` function instance is invoked.
-// It is assumed that the data in `r` will be formatted in the same way as the procompiled (embedded) data stored in `data/sfomuseum.json`.
+// It is assumed that the data in `r` will be formatted in the same way as the precompiled (embedded) data stored in `data/publicart.json`.
 func NewLookupFuncWithReader(ctx context.Context, r io.ReadCloser) PublicArtLookupFunc {
-
-	defer r.Close()
-
-	var publicart_list []*PublicArtWork
-
-	dec := json.NewDecoder(r)
-	err := dec.Decode(&publicart_list)
-
-	if err != nil {
-
-		lookup_func := func(ctx context.Context) {
-			lookup_init_err = err
-		}
-
-		return lookup_func
-	}
-
-	return NewLookupFuncWithPublicArtWorks(ctx, publicart_list)
+	return curatorial.NewLookupTableFuncWithReader[*PublicArtWork](ctx, r)
 }
 
-// NewLookup will return an `PublicArtLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `publicart_list`.
+// NewLookupFuncWithPublicArtWorks will return an `PublicArtLookupFunc` function instance that, when invoked, will populate an `curatorial.Lookup` instance with data stored in `publicart_list`.
 func NewLookupFuncWithPublicArtWorks(ctx context.Context, publicart_list []*PublicArtWork) PublicArtLookupFunc {
-
-	lookup_func := func(ctx context.Context) {
-
-		table := new(sync.Map)
-
-		for _, data := range publicart_list {
-
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				// pass
-			}
-
-			appendData(ctx, table, data)
-		}
-
-		lookup_table = table
-	}
-
-	return lookup_func
+	return curatorial.NewLookupTableFuncWithRecords(ctx, publicart_list)
 }
 
 // NewLookupWithLookupFunc will return an `curatorial.Lookup` instance derived by data compiled using `lookup_func`.
 func NewLookupWithLookupFunc(ctx context.Context, lookup_func PublicArtLookupFunc) (curatorial.Lookup, error) {
 
-	fn := func() {
-		lookup_func(ctx)
-	}
-
-	lookup_init.Do(fn)
-
-	if lookup_init_err != nil {
-		return nil, lookup_init_err
-	}
-
-	l := PublicArtLookup{}
-	return &l, nil
-}
-
-func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
-
-	publicart_list, err := CompilePublicArtWorksData(ctx, iterator_uri, iterator_sources...)
+	l, err := NewTypedLookupWithLookupFunc(ctx, lookup_func)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to compile public art work data, %w", err)
+		return nil, err
 	}
 
-	lookup_func := NewLookupFuncWithPublicArtWorks(ctx, publicart_list)
-	return NewLookupWithLookupFunc(ctx, lookup_func)
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *PublicArtLookup) Find(ctx context.Context, code string) ([]interface{}, error) {
-
-	pointers, ok := lookup_table.Load(code)
-
-	if !ok {
-		return nil, NotFound{code}
-	}
-
-	candidates := make([]interface{}, 0)
-
-	for _, p := range pointers.([]string) {
-
-		if !strings.HasPrefix(p, "pointer:") {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
+// NewTypedLookupWithLookupFunc will return an `curatorial.TypedLookup` instance derived by data compiled using `lookup_func`.
+func NewTypedLookupWithLookupFunc(ctx context.Context, lookup_func PublicArtLookupFunc) (curatorial.TypedLookup[*PublicArtWork], error) {
+	return curatorial.NewLookupTableWithLookupFunc(ctx, lookup_options, lookup_func)
+}
 
-		row, ok := lookup_table.Load(p)
+// NewLookupFromIterator will return an `curatorial.Lookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.Lookup, error) {
 
-		if !ok {
-			return nil, fmt.Errorf("Invalid pointer, %s", p)
-		}
+	l, err := NewTypedLookupFromIterator(ctx, iterator_uri, iterator_sources...)
 
-		candidates = append(candidates, row.(*PublicArtWork))
+	if err != nil {
+		return nil, err
 	}
 
-	return candidates, nil
+	return curatorial.NewUntypedLookup(l), nil
 }
 
-func (l *PublicArtLookup) Append(ctx context.Context, data interface{}) error {
-	return appendData(ctx, lookup_table, data.(*PublicArtWork))
+// NewTypedLookupFromIterator will return an `curatorial.TypedLookup` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+func NewTypedLookupFromIterator(ctx context.Context, iterator_uri string, iterator_sources ...string) (curatorial.TypedLookup[*PublicArtWork], error) {
+	return curatorial.NewLookupTableFromIterator(ctx, lookup_options, iterator_uri, iterator_sources...)
 }
 
-func appendData(ctx context.Context, table *sync.Map, data *PublicArtWork) error {
-
-	idx := atomic.AddInt64(&lookup_idx, 1)
-
-	pointer := fmt.Sprintf("pointer:%d", idx)
-	table.Store(pointer, data)
+// lookupKeys returns the list of codes that 'data' should be indexed by.
+func lookupKeys(data *PublicArtWork) []string {
 
 	str_wofid := strconv.FormatInt(data.WhosOnFirstId, 10)
 	str_sfomid := strconv.FormatInt(data.SFOMuseumId, 10)
@@ -222,37 +151,5 @@ func appendData(ctx context.Context, table *sync.Map, data *PublicArtWork) error
 		possible_codes = append(possible_codes, fmt.Sprintf("sfomuseum:map_id=%s", data.MapId))
 	}
 
-	for _, code := range possible_codes {
-
-		if code == "" {
-			continue
-		}
-
-		pointers := make([]string, 0)
-		has_pointer := false
-
-		others, ok := table.Load(code)
-
-		if ok {
-
-			pointers = others.([]string)
-		}
-
-		for _, dupe := range pointers {
-
-			if dupe == pointer {
-				has_pointer = true
-				break
-			}
-		}
-
-		if has_pointer {
-			continue
-		}
-
-		pointers = append(pointers, pointer)
-		table.Store(code, pointers)
-	}
-
-	return nil
+	return possible_codes
 }
@@ -13,12 +13,28 @@ type PublicArtWork struct {
 	SFOMuseumId   int64  `json:"sfomuseum:object_id"`
 	MapId         string `json:"sfomuseum:map_id"`
 	IsCurrent     int64  `json:"mz:is_current"`
+	IsDeprecated  int64  `json:"mz:is_deprecated,omitempty"`
+	IsCeased      int64  `json:"mz:is_ceased,omitempty"`
+	IsSuperseded  int64  `json:"mz:is_superseded,omitempty"`
 }
 
 func (w *PublicArtWork) String() string {
 	return fmt.Sprintf("\"%s\" %d (%d) (%s) Is current: %d", w.Name, w.WhosOnFirstId, w.SFOMuseumId, w.MapId, w.IsCurrent)
 }
 
+// ExistentialFlags returns the existential properties of the PublicArtWork.
+func (w *PublicArtWork) ExistentialFlags() *curatorial.ExistentialFlags {
+
+	f := &curatorial.ExistentialFlags{
+		IsCurrent:    w.IsCurrent,
+		IsDeprecated: w.IsDeprecated,
+		IsCeased:     w.IsCeased,
+		IsSuperseded: w.IsSuperseded,
+	}
+
+	return f
+}
+
 // Return the current PublicArtWork matching 'code'. Multiple matches throw an error.
 func FindCurrentPublicArtWork(ctx context.Context, code string) (*PublicArtWork, error) {
 
@@ -65,26 +81,53 @@ func FindPublicArtWorksCurrent(ctx context.Context, code string) ([]*PublicArtWo
 
 // Returns all PublicArtWork instances matching 'code' that are marked as current with a custom curatorial.Lookup instance.
 func FindPublicArtWorksCurrentWithLookup(ctx context.Context, lookup curatorial.Lookup, code string) ([]*PublicArtWork, error) {
+	return FindPublicArtWorks(ctx, lookup, code, curatorial.StateCurrent)
+}
 
-	rsp, err := lookup.Find(ctx, code)
+// Returns all PublicArtWork instances matching 'code' that are in any of the existential states defined by 'states' with a custom
+// curatorial.Lookup instance. If 'states' is empty then all the PublicArtWork instances matching 'code' are returned.
+func FindPublicArtWorks(ctx context.Context, lookup curatorial.Lookup, code string, states ...curatorial.ExistentialState) ([]*PublicArtWork, error) {
+
+	typed_lookup := curatorial.AsTypedLookup[*PublicArtWork](lookup)
+
+	rsp, err := typed_lookup.Find(ctx, code)
 
 	if err != nil {
+
+		if curatorial.IsUnexpectedType(err) {
+			return nil, err
+		}
+
 		return nil, NotFound{code}
 	}
 
-	current := make([]*PublicArtWork, 0)
-
-	for _, r := range rsp {
+	matches := make([]*PublicArtWork, 0)
 
-		g := r.(*PublicArtWork)
+	for _, g := range rsp {
 
-		// if g.IsCurrent == 0 {
-		if g.IsCurrent != 1 {
+		if !curatorial.MatchesExistentialStates(g, states...) {
 			continue
 		}
 
-		current = append(current, g)
+		matches = append(matches, g)
 	}
 
-	return current, nil
+	return matches, nil
+}
+
+// SearchPublicArtWorks returns all PublicArtWork instances whose name matches 'query', ranked by score.
+func SearchPublicArtWorks(ctx context.Context, query string) ([]*curatorial.SearchResult[*PublicArtWork], error) {
+
+	lookup, err := NewLookup(ctx, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new lookup, %w", err)
+	}
+
+	return SearchPublicArtWorksWithLookup(ctx, lookup, query)
+}
+
+// SearchPublicArtWorksWithLookup returns all PublicArtWork instances whose name matches 'query', ranked by score, with a custom curatorial.Lookup instance.
+func SearchPublicArtWorksWithLookup(ctx context.Context, lookup curatorial.Lookup, query string) ([]*curatorial.SearchResult[*PublicArtWork], error) {
+	return curatorial.SearchTypedLookup[*PublicArtWork](ctx, lookup, query)
 }
@@ -0,0 +1,25 @@
+package curatorial
+
+import (
+	"context"
+	"fmt"
+)
+
+// RefreshableLookup is an interface for lookups whose data can be reloaded from their underlying source without
+// restarting the process.
+type RefreshableLookup interface {
+	// Refresh reloads the lookup's data from its underlying source.
+	Refresh(context.Context) error
+}
+
+// RefreshLookup reloads the data for 'l' from its underlying source, if 'l' implements the `RefreshableLookup` interface.
+func RefreshLookup(ctx context.Context, l Lookup) error {
+
+	r, ok := l.(RefreshableLookup)
+
+	if !ok {
+		return fmt.Errorf("%T does not support refreshing", l)
+	}
+
+	return r.Refresh(ctx)
+}
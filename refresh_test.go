@@ -0,0 +1,116 @@
+package curatorial
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRefreshLookup(t *testing.T) {
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Keys: func(r *testRecord) []string {
+			return []string{r.Name}
+		},
+	}
+
+	var generation int64
+
+	lookup_func := func(ctx context.Context, l *LookupTable[*testRecord]) error {
+
+		g := atomic.AddInt64(&generation, 1)
+
+		for i := int64(1); i <= 100; i++ {
+
+			r := &testRecord{
+				Id:   i,
+				Name: fmt.Sprintf("generation-%d", g),
+			}
+
+			err := l.Append(ctx, r)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	lu, err := NewLookupTableWithLookupFunc(ctx, opts, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table, %v", err)
+	}
+
+	_, err = lu.Find(ctx, "generation-1")
+
+	if err != nil {
+		t.Fatalf("Failed to find generation-1, %v", err)
+	}
+
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 10; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+
+				count := 0
+
+				for _, err := range lu.Iterate(ctx) {
+
+					if err != nil {
+						t.Errorf("Failed to iterate, %v", err)
+						return
+					}
+
+					count += 1
+				}
+
+				if count != 100 {
+					t.Errorf("Inconsistent snapshot, expected 100 records but got %d", count)
+					return
+				}
+			}
+		}()
+	}
+
+	untyped := NewUntypedLookup(lu)
+
+	for i := 0; i < 5; i++ {
+
+		err := RefreshLookup(ctx, untyped)
+
+		if err != nil {
+			t.Fatalf("Failed to refresh lookup, %v", err)
+		}
+	}
+
+	wg.Wait()
+
+	_, err = lu.Find(ctx, "generation-1")
+
+	if err == nil {
+		t.Fatalf("Expected generation-1 to be absent after refreshing")
+	}
+
+	rsp, err := lu.Find(ctx, "generation-6")
+
+	if err != nil {
+		t.Fatalf("Failed to find generation-6, %v", err)
+	}
+
+	if len(rsp) != 100 {
+		t.Fatalf("Expected 100 results for generation-6, got %d", len(rsp))
+	}
+}
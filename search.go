@@ -0,0 +1,220 @@
+package curatorial
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SearchResult is a record matching a name search and its relative score.
+type SearchResult[T any] struct {
+	// The matching record.
+	Record T
+	// The score for the match. Higher scores are better matches.
+	Score float64
+}
+
+// SearchableLookup is an interface for `TypedLookup` instances that support searching records by name.
+type SearchableLookup[T any] interface {
+	TypedLookup[T]
+	// Search returns the records whose names match a query, ranked by score.
+	Search(context.Context, string) ([]*SearchResult[T], error)
+}
+
+// UntypedSearcher is an interface for `Lookup` instances that support searching records by name.
+type UntypedSearcher interface {
+	// Search returns the records whose names match a query, ranked by score.
+	Search(context.Context, string) ([]interface{}, error)
+}
+
+// SearchLookup returns the records in 'l' whose names match 'query', ranked by score.
+func SearchLookup(ctx context.Context, l Lookup, query string) ([]interface{}, error) {
+
+	s, ok := l.(UntypedSearcher)
+
+	if !ok {
+		return nil, fmt.Errorf("%T does not support searching", l)
+	}
+
+	return s.Search(ctx, query)
+}
+
+// SearchTypedLookup returns the records in 'l' whose names match 'query', ranked by score.
+func SearchTypedLookup[T any](ctx context.Context, l Lookup, query string) ([]*SearchResult[T], error) {
+
+	s, ok := AsTypedLookup[T](l).(SearchableLookup[T])
+
+	if !ok {
+		return nil, fmt.Errorf("%T does not support searching", l)
+	}
+
+	return s.Search(ctx, query)
+}
+
+// The following are scores assigned to individual query tokens depending on how they match a name token.
+const (
+	score_exact  float64 = 1.0
+	score_prefix float64 = 0.75
+	score_fuzzy  float64 = 0.5
+	score_phrase float64 = 0.5
+)
+
+var stopwords = map[string]bool{
+	"a":    true,
+	"an":   true,
+	"and":  true,
+	"at":   true,
+	"for":  true,
+	"from": true,
+	"in":   true,
+	"of":   true,
+	"on":   true,
+	"the":  true,
+	"to":   true,
+}
+
+var diacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s",
+	'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// NormalizeName returns a lower-cased copy of 'name' with diacritics removed and all punctuation replaced by spaces.
+func NormalizeName(name string) string {
+
+	var sb strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+
+		if v, ok := diacritics[r]; ok {
+			sb.WriteString(v)
+			continue
+		}
+
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks (for example decomposed diacritics)
+			continue
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case r == '\'', r == '’':
+			// Collapse possessives and contractions (for example "Gifu's" becomes "gifus")
+			continue
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// TokenizeName returns the list of (normalized) tokens in 'name'.
+func TokenizeName(name string) []string {
+	return strings.Fields(NormalizeName(name))
+}
+
+// scoreToken returns the score for 'query_token' matching 'name_token'.
+func scoreToken(query_token string, name_token string) float64 {
+
+	if query_token == name_token {
+		return score_exact
+	}
+
+	if len(query_token) >= 3 && strings.HasPrefix(name_token, query_token) {
+		return score_prefix
+	}
+
+	max_dist := 0
+
+	switch {
+	case len(query_token) >= 8:
+		max_dist = 2
+	case len(query_token) >= 4:
+		max_dist = 1
+	}
+
+	if max_dist > 0 && levenshtein(query_token, name_token, max_dist) <= max_dist {
+		return score_fuzzy
+	}
+
+	return 0.0
+}
+
+// levenshtein returns the edit distance between 'a' and 'b'. If the distance is greater than 'max' then
+// the return value will be greater than 'max' but not necessarily the actual distance.
+func levenshtein(a string, b string, max int) int {
+
+	ra := []rune(a)
+	rb := []rune(b)
+
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+
+		curr[0] = i
+		row_min := curr[0]
+
+		for j := 1; j <= len(rb); j++ {
+
+			cost := 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			row_min = min(row_min, curr[j])
+		}
+
+		if row_min > max {
+			return max + 1
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// significantTokens returns 'tokens' with stopwords removed, unless every token is a stopword.
+func significantTokens(tokens []string) []string {
+
+	significant := make([]string, 0)
+
+	for _, t := range tokens {
+
+		if !stopwords[t] {
+			significant = append(significant, t)
+		}
+	}
+
+	if len(significant) == 0 {
+		return tokens
+	}
+
+	return significant
+}
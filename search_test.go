@@ -0,0 +1,87 @@
+package curatorial
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+
+	tests := map[string]string{
+		"Four Seasons in Japan: Parasols from the Gifu City Museum": "four seasons in japan parasols from the gifu city museum",
+		"Café  Müller":  "cafe muller",
+		"Café":         "cafe",
+		"Gifu's Museum": "gifus museum",
+	}
+
+	for input, expected := range tests {
+
+		v := NormalizeName(input)
+
+		if v != expected {
+			t.Fatalf("Unexpected normalization for '%s', expected '%s' but got '%s'", input, expected, v)
+		}
+	}
+}
+
+func TestLookupTableSearch(t *testing.T) {
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Keys: func(r *testRecord) []string {
+			return []string{r.Name}
+		},
+		Name: func(r *testRecord) string {
+			return r.Name
+		},
+	}
+
+	records := []*testRecord{
+		&testRecord{Id: 1, Name: "Peephole Cinema"},
+		&testRecord{Id: 2, Name: "Four Seasons in Japan: Parasols from the Gifu City Museum of History"},
+		&testRecord{Id: 3, Name: "Cinéma Paradiso"},
+	}
+
+	lookup_func := NewLookupTableFuncWithRecords(ctx, records)
+	lu, err := NewLookupTableWithLookupFunc(ctx, opts, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table, %v", err)
+	}
+
+	tests := map[string]int64{
+		"peephole":           1,
+		"PEEPHOL":            1,
+		"peephoel cinema":    1,
+		"parasols from Gifu": 2,
+		"cinema paradiso":    3,
+	}
+
+	for q, expected := range tests {
+
+		rsp, err := lu.Search(ctx, q)
+
+		if err != nil {
+			t.Fatalf("Failed to search for '%s', %v", q, err)
+		}
+
+		if len(rsp) == 0 {
+			t.Fatalf("No results for '%s'", q)
+		}
+
+		if rsp[0].Record.Id != expected {
+			t.Fatalf("Unexpected first result for '%s', expected %d but got %d", q, expected, rsp[0].Record.Id)
+		}
+	}
+
+	rsp, err := lu.Search(ctx, "zeppelin")
+
+	if err != nil {
+		t.Fatalf("Failed to search for 'zeppelin', %v", err)
+	}
+
+	if len(rsp) != 0 {
+		t.Fatalf("Expected no results for 'zeppelin'")
+	}
+}
@@ -0,0 +1,767 @@
+package curatorial
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sfomuseum/go-sfomuseum-curatorial/data"
+	"github.com/whosonfirst/go-reader/v2"
+)
+
+// GITHUB_DATA_URL is the base URL for precompiled lookup data stored in the sfomuseum/go-sfomuseum-curatorial repository.
+const GITHUB_DATA_URL string = "https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/"
+
+// KeysFunc returns the list of codes that a record should be indexed by.
+type KeysFunc[T any] func(T) []string
+
+// CompileFunc returns the list of records derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+type CompileFunc[T any] func(ctx context.Context, iterator_uri string, iterator_sources ...string) ([]T, error)
+
+// NameFunc returns the name of a record.
+type NameFunc[T any] func(T) string
+
+// NotFoundFunc returns a package-specific error for a code that can not be found.
+type NotFoundFunc func(code string) error
+
+// NormalizeFunc returns the list of normalized codes to look up when a code can not be found as-is.
+type NormalizeFunc func(code string) []string
+
+// LookupTableOptions defines the record-specific details used by a `LookupTable` instance.
+type LookupTableOptions[T any] struct {
+	// The name of the precompiled data file (for example "exhibitions.json") stored in the `data` package. If the
+	// filename ends in ".gz" the data is assumed to be gzip-compressed. Otherwise a gzip-compressed version of the file
+	// (for example "exhibitions.json.gz") is read from the `data` package in preference to the uncompressed file if present.
+	Filename string
+	// A function to derive the codes that a record should be indexed by.
+	Keys KeysFunc[T]
+	// A function to derive records from a `whosonfirst/go-whosonfirst-iterate` source.
+	Compile CompileFunc[T]
+	// A function to return a package-specific error for a code that can not be found.
+	NotFound NotFoundFunc
+	// An optional function to derive the name of a record. If present the lookup will support searching records by name.
+	Name NameFunc[T]
+	// An optional function to derive normalized variants of a code. If present, codes that can not be found as-is are looked up
+	// using their normalized variants instead. The `Keys` function is expected to index records by the same normalized codes.
+	Normalize NormalizeFunc
+}
+
+// LookupTableFunc is a function that populates a `LookupTable` instance.
+type LookupTableFunc[T any] func(context.Context, *LookupTable[T]) error
+
+// LookupTableSourceFunc is a function that returns a `LookupTableFunc` for (re)populating a `LookupTable` instance from its
+// underlying data source.
+type LookupTableSourceFunc[T any] func(context.Context) (LookupTableFunc[T], error)
+
+// LookupTable is a generic implementation of the `TypedLookup` interface. Each instance maintains its own
+// lookup table so multiple, independent lookups (derived from different sources) can be used side by side.
+type LookupTable[T any] struct {
+	options *LookupTableOptions[T]
+	source  LookupTableSourceFunc[T]
+	state   atomic.Pointer[lookupState[T]]
+	mu      *sync.RWMutex
+}
+
+// lookupState is a snapshot of the data in a `LookupTable` instance. Snapshots are swapped atomically when a lookup is refreshed.
+type lookupState[T any] struct {
+	table *sync.Map
+	idx   int64
+	// The following are only populated if options.Name is not nil
+	tokens map[string][]int64
+	names  map[int64]string
+}
+
+func newLookupState[T any]() *lookupState[T] {
+
+	st := &lookupState[T]{
+		table:  new(sync.Map),
+		tokens: make(map[string][]int64),
+		names:  make(map[int64]string),
+	}
+
+	return st
+}
+
+// NewLookupTable will return a `LookupTable` instance. By default the lookup table is derived from precompiled (embedded)
+// data in `data/{options.Filename}.gz` or `data/{options.Filename}` by passing in `{SCHEME}://` as the URI. If there is no precompiled data a `NoPrecompiledData`
+// error is returned. It is also possible to create a new lookup table
+// with the following URI options:
+//
+//	`{SCHEME}://github`
+//
+// This will cause the lookup table to be derived from the data stored at https://raw.githubusercontent.com/sfomuseum/go-sfomuseum-curatorial/main/data/{options.Filename}. This might be desirable if there have been updates to the underlying data that are not reflected in the locally installed package's pre-compiled data.
+// The following query parameters are supported: `base-url` (fetch data from an alternate base URL, for example a mirror), `cache` (a directory
+// where downloaded data is cached and revalidated using ETags), `timeout` (a `time.ParseDuration` string, default "30s") and `fallback` (if true, the
+// default, use cached or precompiled data when the remote data can not be retrieved). See `NewGitHubOptionsFromQuery` for details.
+//
+//	`{SCHEME}://iterator?uri={URI}&source={SOURCE}`
+//
+// This will cause the lookup table to be derived, at runtime, from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance. `{URI}` should be a valid `whosonfirst/go-whosonfirst-iterate/iterator` URI and `{SOURCE}` is one or more URIs for the iterator to process.
+//
+//	`{SCHEME}://file?path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file stored at `{PATH}` on the local filesystem. If `{PATH}` ends in ".gz" the file is assumed to be gzip-compressed.
+//
+//	`{SCHEME}://reader?uri={URI}&path={PATH}`
+//
+// This will cause the lookup table to be derived from a precompiled JSON file read from a `whosonfirst/go-reader` instance. `{URI}` should be a valid `whosonfirst/go-reader` URI and `{PATH}` is the path of the file to read, relative to that reader. If `{PATH}` is empty then `{options.Filename}` is used.
+//
+// All URIs may also include a `?refresh={DURATION}` query parameter, where `{DURATION}` is a valid `time.ParseDuration` string. If present the
+// lookup table will be reloaded from its source periodically (until 'ctx' is cancelled). See `LookupTable.RefreshEvery` for details.
+func NewLookupTable[T any](ctx context.Context, options *LookupTableOptions[T], uri string) (*LookupTable[T], error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	// Reminder: u.Scheme is used by the curatorial.Lookup constructor
+
+	q := u.Query()
+
+	var source LookupTableSourceFunc[T]
+
+	switch u.Host {
+	case "iterator":
+
+		iterator_uri := q.Get("uri")
+		iterator_sources := q["source"]
+
+		source = newLookupTableSourceWithIterator(options, iterator_uri, iterator_sources...)
+
+	case "file":
+
+		path := q.Get("path")
+
+		if path == "" {
+			return nil, fmt.Errorf("Missing ?path= parameter")
+		}
+
+		source = func(ctx context.Context) (LookupTableFunc[T], error) {
+
+			fh, err := os.Open(path)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to open %s, %w", path, err)
+			}
+
+			r, err := newDataReader(fh, path)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read %s, %w", path, err)
+			}
+
+			return NewLookupTableFuncWithReader[T](ctx, r), nil
+		}
+
+	case "reader":
+
+		reader_uri := q.Get("uri")
+		path := q.Get("path")
+
+		if path == "" {
+			path = options.Filename
+		}
+
+		data_r, err := reader.NewReader(ctx, reader_uri)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create reader for %s, %w", reader_uri, err)
+		}
+
+		source = func(ctx context.Context) (LookupTableFunc[T], error) {
+
+			fh, err := data_r.Read(ctx, path)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read %s, %w", path, err)
+			}
+
+			r, err := newDataReader(fh, path)
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read %s, %w", path, err)
+			}
+
+			return NewLookupTableFuncWithReader[T](ctx, r), nil
+		}
+
+	case "github":
+
+		gh_opts, err := NewGitHubOptionsFromQuery(q)
+
+		if err != nil {
+			return nil, err
+		}
+
+		source = newLookupTableSourceWithGitHub(options, gh_opts)
+
+	default:
+		source = newLookupTableSourceWithEmbeddedData(options)
+	}
+
+	l, err := NewLookupTableWithSource(ctx, options, source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Has("refresh") {
+
+		d, err := time.ParseDuration(q.Get("refresh"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?refresh= parameter, %w", err)
+		}
+
+		go l.RefreshEvery(ctx, d)
+	}
+
+	return l, nil
+}
+
+// gzipReadCloser closes both a `gzip.Reader` instance and its underlying `io.ReadCloser`.
+type gzipReadCloser struct {
+	*gzip.Reader
+	r io.ReadCloser
+}
+
+func (r *gzipReadCloser) Close() error {
+
+	err := r.Reader.Close()
+	r.r.Close()
+
+	return err
+}
+
+// newDataReader returns an `io.ReadCloser` for 'r', decompressing its contents if 'filename' ends in ".gz".
+func newDataReader(r io.ReadCloser, filename string) (io.ReadCloser, error) {
+
+	if !strings.HasSuffix(filename, ".gz") {
+		return r, nil
+	}
+
+	gz, err := gzip.NewReader(r)
+
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("Failed to create gzip reader, %w", err)
+	}
+
+	return &gzipReadCloser{Reader: gz, r: r}, nil
+}
+
+// NewLookupTableFuncWithReader will return an `LookupTableFunc` function instance that, when invoked, will populate a `LookupTable` instance with data stored in `r`.
+// `r` will be closed when the `LookupTableFunc` function instance is invoked.
+// It is assumed that the data in `r` will be formatted as a JSON-encoded list of `T` records, in the same way as the precompiled (embedded) data stored in the `data` package.
+func NewLookupTableFuncWithReader[T any](ctx context.Context, r io.ReadCloser) LookupTableFunc[T] {
+
+	defer r.Close()
+
+	var records []T
+
+	dec := json.NewDecoder(r)
+	err := dec.Decode(&records)
+
+	if err != nil {
+
+		lookup_func := func(ctx context.Context, l *LookupTable[T]) error {
+			return err
+		}
+
+		return lookup_func
+	}
+
+	return NewLookupTableFuncWithRecords(ctx, records)
+}
+
+// NewLookupTableFuncWithRecords will return an `LookupTableFunc` function instance that, when invoked, will populate a `LookupTable` instance with data stored in `records`.
+func NewLookupTableFuncWithRecords[T any](ctx context.Context, records []T) LookupTableFunc[T] {
+
+	lookup_func := func(ctx context.Context, l *LookupTable[T]) error {
+
+		for _, data := range records {
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+				// pass
+			}
+
+			err := l.Append(ctx, data)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return lookup_func
+}
+
+// NewLookupTableWithLookupFunc will return a `LookupTable` instance derived by data compiled using `lookup_func`. Refreshing the
+// lookup table will cause `lookup_func` to be invoked again.
+func NewLookupTableWithLookupFunc[T any](ctx context.Context, options *LookupTableOptions[T], lookup_func LookupTableFunc[T]) (*LookupTable[T], error) {
+
+	source := func(ctx context.Context) (LookupTableFunc[T], error) {
+		return lookup_func, nil
+	}
+
+	return NewLookupTableWithSource(ctx, options, source)
+}
+
+// NewLookupTableFromIterator will return a `LookupTable` instance derived from data emitted by a `whosonfirst/go-whosonfirst-iterate` instance.
+// Refreshing the lookup table will cause the data to be compiled again.
+func NewLookupTableFromIterator[T any](ctx context.Context, options *LookupTableOptions[T], iterator_uri string, iterator_sources ...string) (*LookupTable[T], error) {
+	source := newLookupTableSourceWithIterator(options, iterator_uri, iterator_sources...)
+	return NewLookupTableWithSource(ctx, options, source)
+}
+
+// NewLookupTableWithSource will return a `LookupTable` instance derived by data returned by 'source'. 'source' is invoked
+// when the lookup table is created and every time it is refreshed.
+func NewLookupTableWithSource[T any](ctx context.Context, options *LookupTableOptions[T], source LookupTableSourceFunc[T]) (*LookupTable[T], error) {
+
+	l := &LookupTable[T]{
+		options: options,
+		source:  source,
+		mu:      new(sync.RWMutex),
+	}
+
+	err := l.Refresh(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func newLookupTableSourceWithEmbeddedData[T any](options *LookupTableOptions[T]) LookupTableSourceFunc[T] {
+
+	source := func(ctx context.Context) (LookupTableFunc[T], error) {
+
+		r, err := OpenPrecompiledData(options.Filename)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewLookupTableFuncWithReader[T](ctx, r), nil
+	}
+
+	return source
+}
+
+// OpenPrecompiledData opens the precompiled data for 'filename' stored in the `data` package. Unless 'filename' ends in ".gz" a
+// gzip-compressed version of the file is read in preference to the uncompressed file if present. Compressed data is decompressed
+// transparently. If there is no precompiled data for 'filename' a `NoPrecompiledData` error is returned.
+func OpenPrecompiledData(filename string) (io.ReadCloser, error) {
+
+	for _, candidate := range embeddedFilenames(filename) {
+
+		fh, err := data.FS.Open(candidate)
+
+		if err != nil {
+
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("Failed to load local precompiled data, %w", err)
+		}
+
+		r, err := newDataReader(fh, candidate)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read local precompiled data, %w", err)
+		}
+
+		return r, nil
+	}
+
+	return nil, NoPrecompiledData{filename}
+}
+
+// embeddedFilenames returns the names of the files, in order of preference, to read precompiled data for 'filename' from
+// in the `data` package. Unless 'filename' is already gzip-compressed a gzip-compressed version of the file is preferred.
+func embeddedFilenames(filename string) []string {
+
+	if strings.HasSuffix(filename, ".gz") {
+		return []string{filename}
+	}
+
+	return []string{
+		filename + ".gz",
+		filename,
+	}
+}
+
+func newLookupTableSourceWithIterator[T any](options *LookupTableOptions[T], iterator_uri string, iterator_sources ...string) LookupTableSourceFunc[T] {
+
+	source := func(ctx context.Context) (LookupTableFunc[T], error) {
+
+		if options.Compile == nil {
+			return nil, fmt.Errorf("Lookup does not support compiling data from an iterator")
+		}
+
+		records, err := options.Compile(ctx, iterator_uri, iterator_sources...)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to compile data, %w", err)
+		}
+
+		return NewLookupTableFuncWithRecords(ctx, records), nil
+	}
+
+	return source
+}
+
+// Refresh reloads the lookup table from its underlying source. The new data is loaded in to a separate snapshot which
+// is swapped in atomically once it is complete, so concurrent calls to `Find`, `Search` and `Iterate` always see a
+// consistent view of the data. If there is an error the existing data is left untouched. Any records added using
+// `Append` since the lookup table was last (re)loaded are discarded.
+func (l *LookupTable[T]) Refresh(ctx context.Context) error {
+
+	lookup_func, err := l.source(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := &LookupTable[T]{
+		options: l.options,
+		mu:      new(sync.RWMutex),
+	}
+
+	tmp.state.Store(newLookupState[T]())
+
+	err = lookup_func(ctx, tmp)
+
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state.Store(tmp.state.Load())
+	return nil
+}
+
+// RefreshEvery will call `Refresh` every 'd' until 'ctx' is cancelled. Errors are logged but otherwise ignored; the lookup
+// table continues to serve the last successfully loaded data. This method blocks so it is usually invoked in a goroutine.
+func (l *LookupTable[T]) RefreshEvery(ctx context.Context, d time.Duration) {
+
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+
+			err := l.Refresh(ctx)
+
+			if err != nil {
+				slog.Error("Failed to refresh lookup table", "error", err)
+			}
+		}
+	}
+}
+
+// Find returns all the records matching 'code'. If no records match and the lookup defines a `Normalize` function then the records matching
+// any of the normalized variants of 'code' are returned.
+func (l *LookupTable[T]) Find(ctx context.Context, code string) ([]T, error) {
+
+	st := l.state.Load()
+
+	pointers, ok := st.table.Load(code)
+
+	if ok {
+		return st.records(pointers.([]string))
+	}
+
+	if l.options.Normalize == nil {
+		return nil, l.notFound(code)
+	}
+
+	// Look up each of the normalized variants of 'code' returning the (unique) records matching any of them
+
+	normalized_pointers := make([]string, 0)
+
+	for _, normalized_code := range l.options.Normalize(code) {
+
+		if normalized_code == "" || normalized_code == code {
+			continue
+		}
+
+		pointers, ok := st.table.Load(normalized_code)
+
+		if !ok {
+			continue
+		}
+
+		for _, p := range pointers.([]string) {
+
+			if !slices.Contains(normalized_pointers, p) {
+				normalized_pointers = append(normalized_pointers, p)
+			}
+		}
+	}
+
+	if len(normalized_pointers) == 0 {
+		return nil, l.notFound(code)
+	}
+
+	return st.records(normalized_pointers)
+}
+
+// records returns the records for 'pointers'.
+func (st *lookupState[T]) records(pointers []string) ([]T, error) {
+
+	candidates := make([]T, 0)
+
+	for _, p := range pointers {
+
+		if !strings.HasPrefix(p, "pointer:") {
+			return nil, fmt.Errorf("Invalid pointer, %s", p)
+		}
+
+		row, ok := st.table.Load(p)
+
+		if !ok {
+			return nil, fmt.Errorf("Invalid pointer, %s", p)
+		}
+
+		candidates = append(candidates, row.(T))
+	}
+
+	return candidates, nil
+}
+
+// Append adds 'data' to the lookup table, indexed by the codes returned by the `Keys` function of the lookup's options.
+func (l *LookupTable[T]) Append(ctx context.Context, data T) error {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st := l.state.Load()
+
+	idx := atomic.AddInt64(&st.idx, 1)
+
+	pointer := fmt.Sprintf("pointer:%d", idx)
+	st.table.Store(pointer, data)
+
+	for _, code := range l.options.Keys(data) {
+
+		if code == "" {
+			continue
+		}
+
+		pointers := make([]string, 0)
+		has_pointer := false
+
+		others, ok := st.table.Load(code)
+
+		if ok {
+
+			pointers = others.([]string)
+		}
+
+		for _, dupe := range pointers {
+
+			if dupe == pointer {
+				has_pointer = true
+				break
+			}
+		}
+
+		if has_pointer {
+			continue
+		}
+
+		pointers = append(pointers, pointer)
+		st.table.Store(code, pointers)
+	}
+
+	if l.options.Name != nil {
+
+		name := l.options.Name(data)
+		st.names[idx] = NormalizeName(name)
+
+		for _, t := range TokenizeName(name) {
+
+			idxs := st.tokens[t]
+
+			if len(idxs) > 0 && idxs[len(idxs)-1] == idx {
+				continue
+			}
+
+			st.tokens[t] = append(idxs, idx)
+		}
+	}
+
+	return nil
+}
+
+// Search returns the records whose names match 'query', ranked by score. Names and queries are tokenized and normalized
+// (case and diacritic insensitive) and query tokens match name tokens exactly, by prefix or within a small edit distance.
+func (l *LookupTable[T]) Search(ctx context.Context, query string) ([]*SearchResult[T], error) {
+
+	if l.options.Name == nil {
+		return nil, fmt.Errorf("Lookup does not support searching by name")
+	}
+
+	results := make([]*SearchResult[T], 0)
+
+	query_tokens := significantTokens(TokenizeName(query))
+
+	if len(query_tokens) == 0 {
+		return results, nil
+	}
+
+	norm_query := NormalizeName(query)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	st := l.state.Load()
+
+	scores := make(map[int64]float64)
+
+	for _, qt := range query_tokens {
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			// pass
+		}
+
+		best := make(map[int64]float64)
+
+		for name_token, idxs := range st.tokens {
+
+			score := scoreToken(qt, name_token)
+
+			if score == 0.0 {
+				continue
+			}
+
+			for _, idx := range idxs {
+
+				if score > best[idx] {
+					best[idx] = score
+				}
+			}
+		}
+
+		for idx, score := range best {
+			scores[idx] += score
+		}
+	}
+
+	idxs := make([]int64, 0, len(scores))
+
+	for idx, score := range scores {
+
+		score = score / float64(len(query_tokens))
+
+		if strings.Contains(st.names[idx], norm_query) {
+			score += score_phrase
+		}
+
+		scores[idx] = score
+		idxs = append(idxs, idx)
+	}
+
+	slices.SortFunc(idxs, func(a int64, b int64) int {
+
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		default:
+			return cmp.Compare(a, b)
+		}
+	})
+
+	for _, idx := range idxs {
+
+		row, ok := st.table.Load(fmt.Sprintf("pointer:%d", idx))
+
+		if !ok {
+			return nil, fmt.Errorf("Invalid pointer, pointer:%d", idx)
+		}
+
+		r := &SearchResult[T]{
+			Record: row.(T),
+			Score:  scores[idx],
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// Iterate yields every record in the lookup table, in the order they were appended.
+func (l *LookupTable[T]) Iterate(ctx context.Context) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+
+		st := l.state.Load()
+		count := atomic.LoadInt64(&st.idx)
+
+		for i := int64(1); i <= count; i++ {
+
+			select {
+			case <-ctx.Done():
+				var zero T
+				yield(zero, ctx.Err())
+				return
+			default:
+				// pass
+			}
+
+			row, ok := st.table.Load(fmt.Sprintf("pointer:%d", i))
+
+			if !ok {
+				continue
+			}
+
+			if !yield(row.(T), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (l *LookupTable[T]) notFound(code string) error {
+
+	if l.options.NotFound != nil {
+		return l.options.NotFound(code)
+	}
+
+	return fmt.Errorf("'%s' not found", code)
+}
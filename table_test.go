@@ -0,0 +1,223 @@
+package curatorial
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testRecord struct {
+	Id   int64  `json:"wof:id"`
+	Name string `json:"wof:name"`
+}
+
+func TestLookupTable(t *testing.T) {
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Keys: func(r *testRecord) []string {
+			return []string{
+				fmt.Sprintf("%d", r.Id),
+				r.Name,
+			}
+		},
+	}
+
+	records := []*testRecord{
+		&testRecord{Id: 1, Name: "one"},
+		&testRecord{Id: 2, Name: "two"},
+		&testRecord{Id: 3, Name: "two"},
+	}
+
+	lookup_func := NewLookupTableFuncWithRecords(ctx, records)
+	lu, err := NewLookupTableWithLookupFunc(ctx, opts, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table, %v", err)
+	}
+
+	rsp, err := lu.Find(ctx, "1")
+
+	if err != nil {
+		t.Fatalf("Failed to find '1', %v", err)
+	}
+
+	if len(rsp) != 1 || rsp[0].Id != 1 {
+		t.Fatalf("Unexpected results for '1'")
+	}
+
+	rsp, err = lu.Find(ctx, "two")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'two', %v", err)
+	}
+
+	if len(rsp) != 2 {
+		t.Fatalf("Expected 2 results for 'two', got %d", len(rsp))
+	}
+
+	_, err = lu.Find(ctx, "three")
+
+	if err == nil {
+		t.Fatalf("Expected error finding 'three'")
+	}
+
+	ids := make([]int64, 0)
+
+	for r, err := range lu.Iterate(ctx) {
+
+		if err != nil {
+			t.Fatalf("Failed to iterate lookup table, %v", err)
+		}
+
+		ids = append(ids, r.Id)
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Fatalf("Unexpected iteration order, %v", ids)
+	}
+}
+
+func TestLookupTableNormalize(t *testing.T) {
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Keys: func(r *testRecord) []string {
+			return []string{
+				r.Name,
+				"lower:" + strings.ToLower(r.Name),
+			}
+		},
+		Normalize: func(code string) []string {
+			return []string{
+				"lower:" + strings.ToLower(code),
+			}
+		},
+	}
+
+	records := []*testRecord{
+		&testRecord{Id: 1, Name: "One"},
+		&testRecord{Id: 2, Name: "one"},
+		&testRecord{Id: 3, Name: "Two"},
+	}
+
+	lookup_func := NewLookupTableFuncWithRecords(ctx, records)
+	lu, err := NewLookupTableWithLookupFunc(ctx, opts, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table, %v", err)
+	}
+
+	// Exact matches are returned without consulting the normalized codes
+
+	rsp, err := lu.Find(ctx, "One")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'One', %v", err)
+	}
+
+	if len(rsp) != 1 || rsp[0].Id != 1 {
+		t.Fatalf("Unexpected results for 'One'")
+	}
+
+	rsp, err = lu.Find(ctx, "ONE")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'ONE', %v", err)
+	}
+
+	if len(rsp) != 2 {
+		t.Fatalf("Expected 2 results for 'ONE', got %d", len(rsp))
+	}
+
+	rsp, err = lu.Find(ctx, "two")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'two', %v", err)
+	}
+
+	if len(rsp) != 1 || rsp[0].Id != 3 {
+		t.Fatalf("Unexpected results for 'two'")
+	}
+
+	_, err = lu.Find(ctx, "three")
+
+	if err == nil {
+		t.Fatalf("Expected error finding 'three'")
+	}
+}
+
+func TestNewDataReader(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`[{"wof:id":1,"wof:name":"one"}]`))
+	gz.Close()
+
+	r, err := newDataReader(io.NopCloser(&buf), "test.json.gz")
+
+	if err != nil {
+		t.Fatalf("Failed to create data reader, %v", err)
+	}
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Keys: func(r *testRecord) []string {
+			return []string{r.Name}
+		},
+	}
+
+	lookup_func := NewLookupTableFuncWithReader[*testRecord](ctx, r)
+	lu, err := NewLookupTableWithLookupFunc(ctx, opts, lookup_func)
+
+	if err != nil {
+		t.Fatalf("Failed to create lookup table, %v", err)
+	}
+
+	_, err = lu.Find(ctx, "one")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'one', %v", err)
+	}
+}
+
+func TestNoPrecompiledData(t *testing.T) {
+
+	ctx := context.Background()
+
+	opts := &LookupTableOptions[*testRecord]{
+		Filename: "missing.json.gz",
+	}
+
+	_, err := NewLookupTable(ctx, opts, "test://")
+
+	if !IsNoPrecompiledData(err) {
+		t.Fatalf("Expected NoPrecompiledData error, got %v", err)
+	}
+}
+
+func TestEmbeddedFilenames(t *testing.T) {
+
+	tests := map[string][]string{
+		"collection.json":    []string{"collection.json.gz", "collection.json"},
+		"collection.json.gz": []string{"collection.json.gz"},
+	}
+
+	for filename, expected := range tests {
+
+		candidates := embeddedFilenames(filename)
+
+		if !slices.Equal(candidates, expected) {
+			t.Fatalf("Unexpected filenames for '%s', expected %v but got %v", filename, expected, candidates)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package curatorial
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// TypedLookup is a type-safe variant of the `Lookup` interface for records of type `T`.
+type TypedLookup[T any] interface {
+	// Find returns all the records matching a code.
+	Find(context.Context, string) ([]T, error)
+	// Append adds a record to the lookup.
+	Append(context.Context, T) error
+	// Iterate yields every record in the lookup.
+	Iterate(context.Context) iter.Seq2[T, error]
+}
+
+// UntypedLookup is an adapter that implements the `Lookup` interface for a `TypedLookup` instance.
+type UntypedLookup[T any] struct {
+	typed TypedLookup[T]
+}
+
+// NewUntypedLookup returns a `Lookup` instance wrapping 'typed'.
+func NewUntypedLookup[T any](typed TypedLookup[T]) Lookup {
+
+	l := &UntypedLookup[T]{
+		typed: typed,
+	}
+
+	return l
+}
+
+// Typed returns the underlying `TypedLookup` instance.
+func (l *UntypedLookup[T]) Typed() TypedLookup[T] {
+	return l.typed
+}
+
+func (l *UntypedLookup[T]) Find(ctx context.Context, code string) ([]interface{}, error) {
+
+	rsp, err := l.typed.Find(ctx, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(rsp))
+
+	for idx, r := range rsp {
+		results[idx] = r
+	}
+
+	return results, nil
+}
+
+func (l *UntypedLookup[T]) Append(ctx context.Context, data interface{}) error {
+
+	v, ok := data.(T)
+
+	if !ok {
+		return UnexpectedType{expected: typeName[T](), value: data}
+	}
+
+	return l.typed.Append(ctx, v)
+}
+
+// Search returns the records whose names match 'query', ranked by score, if the underlying `TypedLookup` instance
+// implements the `SearchableLookup` interface.
+func (l *UntypedLookup[T]) Search(ctx context.Context, query string) ([]interface{}, error) {
+
+	s, ok := l.typed.(SearchableLookup[T])
+
+	if !ok {
+		return nil, fmt.Errorf("%T does not support searching", l.typed)
+	}
+
+	rsp, err := s.Search(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(rsp))
+
+	for idx, r := range rsp {
+		results[idx] = r.Record
+	}
+
+	return results, nil
+}
+
+// Refresh reloads the underlying `TypedLookup` instance from its source, if it implements the `RefreshableLookup` interface.
+func (l *UntypedLookup[T]) Refresh(ctx context.Context) error {
+
+	r, ok := l.typed.(RefreshableLookup)
+
+	if !ok {
+		return fmt.Errorf("%T does not support refreshing", l.typed)
+	}
+
+	return r.Refresh(ctx)
+}
+
+// AsTypedLookup returns a `TypedLookup` instance for 'l'. If 'l' is an `UntypedLookup` instance wrapping a `TypedLookup[T]`
+// then the underlying instance is returned. Otherwise 'l' is wrapped in a `TypedLookup` instance whose methods return an
+// `UnexpectedType` error (rather than panicking) when a record is not of type `T`.
+func AsTypedLookup[T any](l Lookup) TypedLookup[T] {
+
+	switch v := l.(type) {
+	case *UntypedLookup[T]:
+		return v.typed
+	default:
+		return &checkedLookup[T]{lookup: l}
+	}
+}
+
+// checkedLookup implements the `TypedLookup` interface for an arbitrary `Lookup` instance.
+type checkedLookup[T any] struct {
+	lookup Lookup
+}
+
+func (l *checkedLookup[T]) Find(ctx context.Context, code string) ([]T, error) {
+
+	rsp, err := l.lookup.Find(ctx, code)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]T, len(rsp))
+
+	for idx, r := range rsp {
+
+		v, ok := r.(T)
+
+		if !ok {
+			return nil, UnexpectedType{expected: typeName[T](), value: r}
+		}
+
+		results[idx] = v
+	}
+
+	return results, nil
+}
+
+func (l *checkedLookup[T]) Append(ctx context.Context, data T) error {
+	return l.lookup.Append(ctx, data)
+}
+
+func (l *checkedLookup[T]) Iterate(ctx context.Context) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, fmt.Errorf("%T does not support iteration", l.lookup))
+	}
+}
+
+func typeName[T any]() string {
+	var zero T
+	return fmt.Sprintf("%T", zero)
+}
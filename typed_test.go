@@ -0,0 +1,60 @@
+package curatorial
+
+import (
+	"context"
+	"testing"
+)
+
+type testLookup struct {
+	records map[string][]interface{}
+}
+
+func (l *testLookup) Find(ctx context.Context, code string) ([]interface{}, error) {
+	return l.records[code], nil
+}
+
+func (l *testLookup) Append(ctx context.Context, data interface{}) error {
+	return nil
+}
+
+func TestAsTypedLookup(t *testing.T) {
+
+	ctx := context.Background()
+
+	lu := &testLookup{
+		records: map[string][]interface{}{
+			"ok":  []interface{}{"hello"},
+			"bad": []interface{}{1},
+		},
+	}
+
+	typed_lu := AsTypedLookup[string](lu)
+
+	rsp, err := typed_lu.Find(ctx, "ok")
+
+	if err != nil {
+		t.Fatalf("Failed to find 'ok', %v", err)
+	}
+
+	if len(rsp) != 1 || rsp[0] != "hello" {
+		t.Fatalf("Unexpected results for 'ok'")
+	}
+
+	_, err = typed_lu.Find(ctx, "bad")
+
+	if !IsUnexpectedType(err) {
+		t.Fatalf("Expected UnexpectedType error, got %v", err)
+	}
+
+	untyped_lu := NewUntypedLookup(typed_lu)
+
+	if AsTypedLookup[string](untyped_lu) != typed_lu {
+		t.Fatalf("Expected AsTypedLookup to unwrap UntypedLookup")
+	}
+
+	err = untyped_lu.Append(ctx, 1)
+
+	if !IsUnexpectedType(err) {
+		t.Fatalf("Expected UnexpectedType error, got %v", err)
+	}
+}